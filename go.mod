@@ -1,8 +1,16 @@
 module SkelChunker
 
-go 1.21
+go 1.23
 
 require (
 	github.com/sashabaranov/go-openai v1.38.1
+	github.com/tree-sitter/go-tree-sitter v0.25.0
+	github.com/tree-sitter/tree-sitter-c-sharp v0.23.1
+	github.com/tree-sitter/tree-sitter-go v0.25.0
+	github.com/tree-sitter/tree-sitter-java v0.23.5
+	github.com/tree-sitter/tree-sitter-javascript v0.23.1
+	github.com/tree-sitter/tree-sitter-python v0.25.0
 	golang.org/x/crypto v0.18.0
 )
+
+require github.com/mattn/go-pointer v0.0.1 // indirect
@@ -9,9 +9,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"bytes"
 )
 
@@ -20,7 +22,12 @@ type CompactResult struct {
 	Path       string              `json:"path"`
 	Filename   string              `json:"filename"`
 	MD5        string              `json:"md5"`
+	Version    int                 `json:"version,omitempty"`
+	Parser     string              `json:"parser,omitempty"`
+	EmbeddingModel string          `json:"embeddingModel,omitempty"`
 	Embeddings json.RawMessage     `json:"embeddings,omitempty"`
+	Imports    []model.Import       `json:"imports,omitempty"`
+	Identifiers []model.Identifier  `json:"identifiers"`
 	Skeleton   []model.SkeletonNode `json:"skeleton"`
 	Chunks     []CompactChunk       `json:"chunks"`
 }
@@ -29,6 +36,7 @@ type CompactResult struct {
 type CompactChunk struct {
 	MD5        string          `json:"md5"`
 	Text       string          `json:"text"`
+	Range      *model.Range    `json:"range,omitempty"`
 	Embeddings json.RawMessage `json:"embeddings,omitempty"`
 }
 
@@ -37,6 +45,12 @@ type Analyzer struct {
 	parserFactory *parser.ParserFactory
 	embeddingService embeddings.EmbeddingService
 	embeddingConfig *embeddings.Config
+
+	chunkEmbeddings *chunkEmbeddingStore // 이전 결과와 이번 실행에서 얻은 청크 임베딩 (여러 파일이 공유)
+	output          OutputConfig
+	renames         *renameTracker // TrackRenames로 찾은, 원본이 없어진 결과 파일
+	unchanged       map[string]bool // ReuseResults로 지정한, 소스를 읽지 않고 저장된 결과를 쓸 파일 (절대 경로)
+	stats           embeddingCounters
 }
 
 // NewAnalyzer는 새로운 Analyzer 인스턴스를 생성합니다.
@@ -45,48 +59,104 @@ func NewAnalyzer(parserFactory *parser.ParserFactory, embeddingService embedding
 		parserFactory: parserFactory,
 		embeddingService: embeddingService,
 		embeddingConfig: embeddingConfig,
+		chunkEmbeddings: newChunkEmbeddingStore(),
 	}
 }
 
-// AnalyzeFile은 단일 파일을 분석하여 결과를 반환합니다.
+// ParsedFile은 파싱 단계의 결과로, 임베딩 단계에 필요한 텍스트와 출력 파일 경로를 함께 담습니다.
+// 한 파일의 텍스트가 여러 배치에 나뉠 수 있으므로 임베딩 결과는 mu로 보호합니다.
+type ParsedFile struct {
+	Result *model.AnalysisResult
+	Cached   bool   // 파일이 변경되지 않아 기존 결과(임베딩 포함)를 재사용함
+	Parser   string // 사용한 파서 이름
+	Language string // 파서가 처리하는 언어
+
+	skelChunkerPath string
+	texts           []string // 임베딩할 텍스트 (파일 전체를 나눈 조각들, 그 다음 재사용할 수 없는 청크별 텍스트)
+	fileTexts       int      // texts 중 파일 전체 임베딩에 해당하는 개수
+	chunkIndexes    []int    // fileTexts 이후의 텍스트가 속한 청크 위치
+
+	mu        sync.Mutex
+	vectors   [][]float32 // texts와 같은 순서의 임베딩
+	remaining int         // 아직 임베딩을 받지 못한 텍스트 수
+	embedErr  error
+}
+
+// AnalyzeFile은 단일 파일을 파싱하고 임베딩까지 생성하여 결과를 반환합니다.
 func (a *Analyzer) AnalyzeFile(filePath string) (*model.AnalysisResult, error) {
+	parsed, err := a.ParseFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.EmbedFile(parsed); err != nil {
+		return nil, err
+	}
+	return parsed.Result, nil
+}
+
+// ParseFile은 단일 파일을 파싱하여 스켈레톤과 청크를 만듭니다. 임베딩은 EmbedFile에서 생성합니다.
+// Analyzer와 파서는 상태를 공유하지 않으므로 여러 고루틴에서 동시에 호출할 수 있습니다.
+func (a *Analyzer) ParseFile(filePath string) (*ParsedFile, error) {
+	// 바뀌지 않은 것으로 지정된 파일은 소스를 읽지 않고 저장된 결과를 사용
+	if parsed, ok := a.loadUnchanged(filePath); ok {
+		return parsed, nil
+	}
+
 	// 파일 읽기
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// 파일 확장자 확인
-	ext := filepath.Ext(filePath)
-	parser, err := a.parserFactory.GetParser(ext)
+	// 파일 확장자와 내용으로 파서 선택
+	fileParser, err := a.parserFactory.ParserForFile(filePath, content)
 	if err != nil {
-		return nil, fmt.Errorf("no parser available for extension %s: %w", ext, err)
+		return nil, fmt.Errorf("no parser available for file %s: %w", filePath, err)
 	}
 
 	// MD5 해시 계산
 	hash := md5.Sum(content)
 	md5Hash := hex.EncodeToString(hash[:])
 
-	// SkelChunker 파일 경로 생성
-	baseFileName := filepath.Base(filePath)
-	ext = filepath.Ext(baseFileName)
-	skelChunkerPath := filepath.Join(filepath.Dir(filePath), baseFileName[:len(baseFileName)-len(ext)]+".SkelChunker")
+	// SkelChunker 파일 경로 생성 (결과 폴더에서 소스 트리를 따르며 원본 확장자를 유지)
+	relPath := a.RelativePath(filePath)
+	skelChunkerPath := a.ResultPath(relPath)
 
-	// 기존 SkelChunker 파일이 있는지 확인
+	// 기존 SkelChunker 파일이 있는지 확인 (없으면 이전 버전이 소스 파일 옆에 저장한 결과,
+	// 그래도 없으면 같은 내용으로 옮겨지기 전 경로의 결과를 사용)
+	existingData, err := os.ReadFile(skelChunkerPath)
+	if os.IsNotExist(err) {
+		existingData, err = os.ReadFile(legacyResultPath(filePath))
+	}
+	if os.IsNotExist(err) {
+		existingData, err = a.renamedResult(md5Hash, relPath)
+	}
 	var existingResult *model.AnalysisResult
-	if existingData, err := os.ReadFile(skelChunkerPath); err == nil {
+	if err == nil {
 		existingResult = &model.AnalysisResult{}
 		if err := json.Unmarshal(existingData, existingResult); err == nil {
-			// 파일 전체 MD5 비교
-			if existingResult.MD5 == md5Hash {
+			// 다른 임베딩 모델이나 차원으로 만든 임베딩은 섞이지 않도록 재사용하지 않음
+			reusable := a.embeddingService == nil || a.embeddingsCompatible(existingResult)
+
+			// 파일 전체 MD5와 함께 결과 버전과 파서가 같아야 결과를 그대로 사용
+			if existingResult.MD5 == md5Hash && reusable && a.resultCurrent(existingResult, fileParser) {
 				// 파일이 변경되지 않았으므로 기존 결과 반환
-				return existingResult, nil
+				existingResult.Path, existingResult.Filename = path.Dir(relPath), path.Base(relPath)
+				if a.embeddingService != nil {
+					a.chunkEmbeddings.addChunks(existingResult.Chunks)
+				}
+				return &ParsedFile{Result: existingResult, Cached: true, Parser: fileParser.GetName(), Language: fileParser.GetLanguage()}, nil
+			}
+
+			// 파일이 변경되었어도 바뀌지 않은 청크의 임베딩은 재사용
+			if a.embeddingService != nil && reusable {
+				a.chunkEmbeddings.addChunks(existingResult.Chunks)
 			}
 		}
 	}
 
 	// 파일 분석
-	skeleton, chunks, err := parser.Parse(string(content))
+	skeleton, chunks, err := fileParser.Parse(string(content))
 	if err != nil {
 		// 오류 발생 시 기존 SkelChunker 파일이 있다면 삭제
 		if _, err := os.Stat(skelChunkerPath); err == nil {
@@ -104,19 +174,25 @@ func (a *Analyzer) AnalyzeFile(filePath string) (*model.AnalysisResult, error) {
 		// 파일 전체를 하나의 청크로 생성
 		chunks = []model.Chunk{
 			{
-				MD5:  md5Hash,
-				Text: string(content),
+				MD5:   md5Hash,
+				Text:  string(content),
+				Range: parser.FileRange(string(content)),
 			},
 		}
 	}
 
 	// 결과 생성
 	result := &model.AnalysisResult{
-		Path:     filepath.Dir(filePath),
-		Filename: filepath.Base(filePath),
+		Path:     path.Dir(relPath),
+		Filename: path.Base(relPath),
 		MD5:      md5Hash,
+		Version:  ResultVersion,
+		Parser:   fileParser.GetName(),
+		Imports:  extractImports(fileParser, string(content)),
 		Skeleton: skeleton,
 		Chunks:   chunks,
+
+		Identifiers: extractIdentifiers(fileParser, string(content)),
 	}
 
 	// 파일 전체를 처리한 후 최종 검증
@@ -124,79 +200,114 @@ func (a *Analyzer) AnalyzeFile(filePath string) (*model.AnalysisResult, error) {
 	if result.Chunks == nil || len(result.Chunks) == 0 {
 		result.Chunks = []model.Chunk{
 			{
-				MD5:  md5Hash,
-				Text: string(content),
+				MD5:   md5Hash,
+				Text:  string(content),
+				Range: parser.FileRange(string(content)),
 			},
 		}
 	}
 
-	// 임베딩 서비스가 있는 경우 임베딩 생성 수행
+	parsed := &ParsedFile{Result: result, Parser: fileParser.GetName(), Language: fileParser.GetLanguage(), skelChunkerPath: skelChunkerPath}
 	if a.embeddingService != nil {
-		// 파일 전체 임베딩 생성
-		fileEmbeddings, err := a.createEmbeddingsForText(string(content))
-		if err != nil {
-			// 임베딩 생성 오류 시 기존 SkelChunker 파일이 있다면 삭제
-			if _, err := os.Stat(skelChunkerPath); err == nil {
-				os.Remove(skelChunkerPath)
-			}
-			return nil, fmt.Errorf("failed to create embeddings for file: %w", err)
+		if err := a.prepareEmbedding(parsed, string(content)); err != nil {
+			return nil, err
 		}
-		result.Embeddings = fileEmbeddings
+	}
+	return parsed, nil
+}
 
-		// 각 청크에 대한 임베딩 생성
-		for i := range result.Chunks {
-			// 청크 전처리
-			chunkText := preprocessCodeForEmbedding(result.Chunks[i].Text)
-			
-			// 청크가 너무 큰 경우 분할하여 임베딩 생성
-			chunkEmbeddings, err := a.createEmbeddingsForText(chunkText)
-			if err != nil {
-				// 임베딩 생성 오류 시 기존 SkelChunker 파일이 있다면 삭제
-				if _, err := os.Stat(skelChunkerPath); err == nil {
-					os.Remove(skelChunkerPath)
-				}
-				return nil, fmt.Errorf("failed to create embeddings for chunk: %w", err)
-			}
-			
-			// 청크가 분할된 경우 첫 번째 임베딩만 사용
-			if len(chunkEmbeddings) > 0 {
-				result.Chunks[i].Embeddings = chunkEmbeddings[0]
-			}
+// ReuseResults는 바뀌지 않은 것으로 알려진 파일들을 지정합니다 (증분 색인에서 git이 바뀌지 않았다고 알려준 파일).
+// ParseFile은 이 파일들의 소스를 읽거나 다시 토큰화하지 않고 저장된 결과를 사용합니다.
+// 저장된 결과가 없거나, 다른 결과 버전이나 파서로 만든 결과이거나, 다른 임베딩 모델로 만든 결과이면 평소처럼 분석합니다.
+// 분석을 시작하기 전에 호출해야 합니다.
+func (a *Analyzer) ReuseResults(files []string) {
+	a.unchanged = make(map[string]bool, len(files))
+	for _, file := range files {
+		if absPath, err := filepath.Abs(file); err == nil {
+			a.unchanged[absPath] = true
 		}
 	}
-
-	return result, nil
 }
 
-// createEmbeddingsForText는 주어진 텍스트에 대한 임베딩을 생성합니다.
-func (a *Analyzer) createEmbeddingsForText(text string) ([][]float32, error) {
-	// 임베딩 서비스가 없는 경우 빈 배열 반환
-	if a.embeddingService == nil {
-		return nil, nil
+// loadUnchanged는 ReuseResults로 지정된 파일의 저장된 결과를 소스를 읽지 않고 읽습니다.
+// 지정되지 않았거나 결과를 재사용할 수 없으면 false를 반환합니다.
+func (a *Analyzer) loadUnchanged(filePath string) (*ParsedFile, bool) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil || !a.unchanged[absPath] {
+		return nil, false
+	}
+	fileParser, err := a.parserFactory.DetectFile(filePath)
+	if err != nil {
+		return nil, false
+	}
+	relPath := a.RelativePath(filePath)
+	data, err := os.ReadFile(a.ResultPath(relPath))
+	if err != nil {
+		return nil, false
+	}
+	result := &model.AnalysisResult{}
+	if err := json.Unmarshal(data, result); err != nil || result.MD5 == "" || !a.resultCurrent(result, fileParser) {
+		return nil, false
 	}
+	if a.embeddingService != nil && !a.embeddingsCompatible(result) {
+		return nil, false
+	}
+	result.Path, result.Filename = path.Dir(relPath), path.Base(relPath)
+	if a.embeddingService != nil {
+		a.chunkEmbeddings.addChunks(result.Chunks)
+	}
+	return &ParsedFile{Result: result, Cached: true, Parser: fileParser.GetName(), Language: fileParser.GetLanguage()}, true
+}
 
-	// 텍스트 전처리
-	text = preprocessCodeForEmbedding(text)
+// resultCurrent는 저장된 결과가 현재 결과 버전과 주어진 파서로 만들어졌는지 확인합니다.
+// 이전 버전이 저장한 결과나 파서 매핑이 바뀐 파일의 결과는 다시 분석해야 합니다.
+func (a *Analyzer) resultCurrent(result *model.AnalysisResult, fileParser parser.Parser) bool {
+	return result.Version == ResultVersion && result.Parser == fileParser.GetName()
+}
 
-	// 텍스트 크기가 제한을 초과하는 경우 청크로 분할
-	chunks, err := a.embeddingService.ChunkText(text, a.embeddingConfig.MaxTextSize)
-	if err != nil {
-		return nil, fmt.Errorf("failed to chunk text: %w", err)
+// EmbedFile은 파싱된 파일 전체와 각 청크의 임베딩을 생성합니다.
+// 임베딩 서비스가 없거나 기존 결과를 재사용한 파일은 아무것도 하지 않습니다.
+// 여러 파일의 텍스트를 한 요청으로 묶으려면 Pipeline을 사용합니다.
+func (a *Analyzer) EmbedFile(parsed *ParsedFile) error {
+	if !parsed.needsEmbedding() {
+		return nil
 	}
 
-	// 각 청크에 대해 임베딩 생성
-	var embeddings [][]float32
-	for _, chunk := range chunks {
-		embedding, err := a.embeddingService.CreateEmbedding(chunk)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create embedding: %w", err)
-		}
-		embeddings = append(embeddings, embedding)
+	batcher := a.newEmbeddingBatcher()
+	batches := batcher.add(parsed)
+	if batch := batcher.flush(); batch != nil {
+		batches = append(batches, batch)
+	}
+	for _, batch := range batches {
+		a.embedBatch(batch)
 	}
+	return parsed.embedErr
+}
+
+// extractImports는 파서가 지원하는 경우 소스의 의존 구문을 추출합니다.
+func extractImports(fileParser parser.Parser, content string) []model.Import {
+	if extractor, ok := fileParser.(parser.ImportExtractor); ok {
+		return extractor.ExtractImports(content)
+	}
+	return nil
+}
 
-	return embeddings, nil
+// extractIdentifiers는 파서가 지원하는 경우 심볼 색인용 식별자 위치를 추출합니다.
+func extractIdentifiers(fileParser parser.Parser, content string) []model.Identifier {
+	if extractor, ok := fileParser.(parser.IdentifierExtractor); ok {
+		return extractor.ExtractIdentifiers(content)
+	}
+	return nil
 }
 
+// ResultVersion은 결과 파일에 저장하는 분석 결과(스켈레톤, 청크, 의존 구문, 식별자) 형식의 버전입니다.
+// 파서 출력이나 결과 형식을 바꾸면 올려서 바뀌지 않은 파일의 이전 결과도 다시 만들게 합니다.
+const ResultVersion = 1
+
+// PreprocessingVersion은 preprocessCodeForEmbedding 규칙의 버전입니다.
+// 규칙을 바꾸면 올려서 디스크 임베딩 캐시의 이전 항목을 쓰지 않게 합니다.
+const PreprocessingVersion = 1
+
 // preprocessCodeForEmbedding는 코드 텍스트를 임베딩에 적합하게 전처리합니다.
 func preprocessCodeForEmbedding(text string) string {
 	// 불필요한 공백 제거
@@ -218,11 +329,11 @@ func preprocessCodeForEmbedding(text string) string {
 
 // SaveResult는 분석 결과를 파일로 저장합니다.
 func (a *Analyzer) SaveResult(result *model.AnalysisResult) error {
-	// 파일명 생성
-	baseFileName := filepath.Base(result.Filename)
-	ext := filepath.Ext(baseFileName)
-	outputFileName := baseFileName[:len(baseFileName)-len(ext)] + ".SkelChunker"
-	outputPath := filepath.Join(result.Path, outputFileName)
+	// 결과 파일 경로 생성 (결과 폴더에 소스 트리와 같은 하위 폴더를 만듦)
+	outputPath := a.ResultPath(path.Join(result.Path, result.Filename))
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output folder: %w", err)
+	}
 
 	// JSON 출력 버퍼
 	var buf bytes.Buffer
@@ -234,6 +345,15 @@ func (a *Analyzer) SaveResult(result *model.AnalysisResult) error {
 	buf.WriteString(fmt.Sprintf("  \"path\": %s,\n", jsonString(result.Path)))
 	buf.WriteString(fmt.Sprintf("  \"filename\": %s,\n", jsonString(result.Filename)))
 	buf.WriteString(fmt.Sprintf("  \"md5\": %s,\n", jsonString(result.MD5)))
+	if result.Version != 0 {
+		buf.WriteString(fmt.Sprintf("  \"version\": %d,\n", result.Version))
+	}
+	if result.Parser != "" {
+		buf.WriteString(fmt.Sprintf("  \"parser\": %s,\n", jsonString(result.Parser)))
+	}
+	if result.EmbeddingModel != "" {
+		buf.WriteString(fmt.Sprintf("  \"embeddingModel\": %s,\n", jsonString(result.EmbeddingModel)))
+	}
 	
 	// 임베딩이 있으면 한 줄로 직접 작성
 	if result.Embeddings != nil {
@@ -258,6 +378,30 @@ func (a *Analyzer) SaveResult(result *model.AnalysisResult) error {
 		buf.WriteString("],\n")
 	}
 	
+	// 의존 구문이 있으면 작성
+	if len(result.Imports) > 0 {
+		importsBytes, err := json.MarshalIndent(result.Imports, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal imports: %w", err)
+		}
+		buf.WriteString("  \"imports\": ")
+		buf.Write(importsBytes)
+		buf.WriteString(",\n")
+	}
+	
+	// 식별자 위치는 한 줄로 작성 (빈 목록도 작성하여 식별자를 저장한 결과임을 표시)
+	identifiers := result.Identifiers
+	if identifiers == nil {
+		identifiers = []model.Identifier{}
+	}
+	identifiersBytes, err := json.Marshal(identifiers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal identifiers: %w", err)
+	}
+	buf.WriteString("  \"identifiers\": ")
+	buf.Write(identifiersBytes)
+	buf.WriteString(",\n")
+	
 	// 스켈레톤 객체 마샬링
 	skeletonBytes, err := json.MarshalIndent(result.Skeleton, "  ", "  ")
 	if err != nil {
@@ -279,6 +423,13 @@ func (a *Analyzer) SaveResult(result *model.AnalysisResult) error {
 		buf.WriteString(fmt.Sprintf("      \"md5\": %s,\n", jsonString(chunk.MD5)))
 		buf.WriteString(fmt.Sprintf("      \"text\": %s", jsonString(chunk.Text)))
 		
+		// 청크 범위가 있으면 한 줄로 작성
+		if chunk.Range != nil {
+			rangeBytes, _ := json.Marshal(chunk.Range)
+			buf.WriteString(",\n      \"range\": ")
+			buf.Write(rangeBytes)
+		}
+		
 		// 청크 임베딩이 있으면 한 줄로 직접 작성
 		if chunk.Embeddings != nil {
 			buf.WriteString(",\n      \"embeddings\": ")
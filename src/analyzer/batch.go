@@ -0,0 +1,216 @@
+package analyzer
+
+import (
+	"SkelChunker/src/embeddings"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// embeddingInput은 배치에 담긴 텍스트 하나와 그 텍스트를 요청한 파일입니다.
+type embeddingInput struct {
+	file  *ParsedFile
+	index int // file.texts에서의 위치
+}
+
+// embeddingBatch는 임베딩 요청 한 번으로 보낼 텍스트 묶음입니다. 여러 파일의 텍스트가 섞일 수 있습니다.
+type embeddingBatch struct {
+	inputs []embeddingInput
+	tokens int // 추정 토큰 수의 합
+}
+
+// embeddingBatcher는 파일들의 임베딩 텍스트를 항목 수와 토큰 수 제한에 맞는 배치로 묶습니다.
+type embeddingBatcher struct {
+	maxItems  int
+	maxTokens int
+	current   *embeddingBatch
+}
+
+// newEmbeddingBatcher는 임베딩 설정의 배치 제한으로 새로운 배처를 생성합니다.
+func (a *Analyzer) newEmbeddingBatcher() *embeddingBatcher {
+	defaults := embeddings.DefaultConfig()
+	batcher := &embeddingBatcher{maxItems: defaults.BatchSize, maxTokens: defaults.BatchTokens}
+	if a.embeddingConfig != nil && a.embeddingConfig.BatchSize > 0 {
+		batcher.maxItems = a.embeddingConfig.BatchSize
+	}
+	if a.embeddingConfig != nil && a.embeddingConfig.BatchTokens > 0 {
+		batcher.maxTokens = a.embeddingConfig.BatchTokens
+	}
+	return batcher
+}
+
+// add는 파일의 텍스트를 현재 배치에 담고, 제한에 도달하여 더 담을 수 없게 된 배치들을 반환합니다.
+// 제한보다 큰 텍스트 하나는 단독 배치가 됩니다.
+func (b *embeddingBatcher) add(parsed *ParsedFile) []*embeddingBatch {
+	var full []*embeddingBatch
+	for i, text := range parsed.texts {
+		tokens := embeddings.EstimateTokens(text)
+		if b.current != nil && (len(b.current.inputs) >= b.maxItems || b.current.tokens+tokens > b.maxTokens) {
+			full = append(full, b.current)
+			b.current = nil
+		}
+		if b.current == nil {
+			b.current = &embeddingBatch{}
+		}
+		b.current.inputs = append(b.current.inputs, embeddingInput{file: parsed, index: i})
+		b.current.tokens += tokens
+	}
+	return full
+}
+
+// flush는 아직 보내지 않은 배치를 반환합니다. 남은 텍스트가 없으면 nil입니다.
+func (b *embeddingBatcher) flush() *embeddingBatch {
+	batch := b.current
+	b.current = nil
+	return batch
+}
+
+// prepareEmbedding은 파일 전체와 각 청크에서 임베딩할 텍스트를 만듭니다.
+// 파일 전체는 크기 제한에 맞게 나눈 조각마다, 청크는 첫 번째 조각 하나만 임베딩합니다.
+// 같은 내용의 청크 임베딩이 저장소에 있으면 요청하지 않고 재사용합니다.
+func (a *Analyzer) prepareEmbedding(parsed *ParsedFile, content string) error {
+	fileTexts, err := a.embeddingService.ChunkText(preprocessCodeForEmbedding(content), a.embeddingConfig.MaxTextSize)
+	if err != nil {
+		return fmt.Errorf("failed to chunk text: %w", err)
+	}
+	// 빈 텍스트는 임베딩 API가 거부하므로 요청하지 않음
+	texts := nonEmptyTexts(fileTexts)
+	fileTextCount := len(texts)
+
+	var chunkIndexes []int
+	for i := range parsed.Result.Chunks {
+		chunk := &parsed.Result.Chunks[i]
+		if vector, ok := a.chunkEmbeddings.get(chunkKey(*chunk)); ok {
+			chunk.Embeddings = vector
+			a.stats.reused.Add(1)
+			continue
+		}
+
+		chunkTexts, err := a.embeddingService.ChunkText(preprocessCodeForEmbedding(chunk.Text), a.embeddingConfig.MaxTextSize)
+		if err != nil {
+			return fmt.Errorf("failed to chunk text: %w", err)
+		}
+		chunkTexts = nonEmptyTexts(chunkTexts)
+		if len(chunkTexts) == 0 {
+			// 공백뿐인 청크는 임베딩 없이 둠
+			continue
+		}
+		texts = append(texts, chunkTexts[0])
+		chunkIndexes = append(chunkIndexes, i)
+	}
+
+	parsed.Result.EmbeddingModel = a.embeddingConfig.ModelName
+	parsed.texts = texts
+	parsed.fileTexts = fileTextCount
+	parsed.chunkIndexes = chunkIndexes
+	parsed.vectors = make([][]float32, len(texts))
+	parsed.remaining = len(texts)
+	return nil
+}
+
+// nonEmptyTexts는 공백뿐인 텍스트를 제외한 목록을 반환합니다.
+func nonEmptyTexts(texts []string) []string {
+	result := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if strings.TrimSpace(text) != "" {
+			result = append(result, text)
+		}
+	}
+	return result
+}
+
+// needsEmbedding은 파일에 아직 임베딩을 받지 못한 텍스트가 있는지 확인합니다.
+func (p *ParsedFile) needsEmbedding() bool {
+	return len(p.texts) > 0
+}
+
+// embedBatch는 배치를 요청 한 번으로 임베딩하여 각 텍스트를 요청한 파일에 돌려주고,
+// 이 배치로 모든 텍스트의 임베딩을 받은 파일들을 반환합니다. 여러 고루틴에서 동시에 호출할 수 있습니다.
+// 여러 파일이 섞인 배치의 요청이 실패하면 파일별로 다시 요청하여, 요청이 거부된 파일만 실패합니다.
+// 실패한 파일의 오류는 각 파일의 embedErr에 남습니다.
+func (a *Analyzer) embedBatch(batch *embeddingBatch) []*ParsedFile {
+	vectors, err := a.requestEmbeddings(batch.inputs)
+	errs := make([]error, len(batch.inputs))
+	if err != nil {
+		byFile := make(map[*ParsedFile][]int)
+		var files []*ParsedFile
+		for i, input := range batch.inputs {
+			if _, exists := byFile[input.file]; !exists {
+				files = append(files, input.file)
+			}
+			byFile[input.file] = append(byFile[input.file], i)
+		}
+		vectors = make([][]float32, len(batch.inputs))
+		for _, file := range files {
+			indexes := byFile[file]
+			fileErr := err
+			if len(files) > 1 {
+				inputs := make([]embeddingInput, len(indexes))
+				for j, i := range indexes {
+					inputs[j] = batch.inputs[i]
+				}
+				var fileVectors [][]float32
+				if fileVectors, fileErr = a.requestEmbeddings(inputs); fileErr == nil {
+					for j, i := range indexes {
+						vectors[i] = fileVectors[j]
+					}
+				}
+			}
+			for _, i := range indexes {
+				errs[i] = fileErr
+			}
+		}
+	}
+
+	var completed []*ParsedFile
+	for i, input := range batch.inputs {
+		file := input.file
+		file.mu.Lock()
+		if errs[i] != nil {
+			if file.embedErr == nil {
+				file.embedErr = fmt.Errorf("failed to create embeddings: %w", errs[i])
+			}
+		} else {
+			file.vectors[input.index] = vectors[i]
+		}
+		file.remaining--
+		if file.remaining == 0 {
+			a.finishEmbedding(file)
+			completed = append(completed, file)
+		}
+		file.mu.Unlock()
+	}
+	return completed
+}
+
+// requestEmbeddings는 입력들의 텍스트를 요청 한 번으로 임베딩하여 입력 순서대로 반환합니다.
+func (a *Analyzer) requestEmbeddings(inputs []embeddingInput) ([][]float32, error) {
+	texts := make([]string, len(inputs))
+	for i, input := range inputs {
+		texts[i] = input.file.texts[input.index]
+	}
+	vectors, err := a.embeddingService.CreateEmbeddings(texts)
+	if err == nil && len(vectors) != len(texts) {
+		err = fmt.Errorf("embedding service returned %d embeddings for %d texts", len(vectors), len(texts))
+	}
+	return vectors, err
+}
+
+// finishEmbedding은 받은 임베딩을 파일 전체와 각 청크에 나누어 담고, 청크 임베딩을 저장소에 추가합니다.
+// 임베딩에 실패한 파일은 기존 SkelChunker 파일이 있다면 삭제합니다.
+func (a *Analyzer) finishEmbedding(p *ParsedFile) {
+	if p.embedErr != nil {
+		if _, err := os.Stat(p.skelChunkerPath); err == nil {
+			os.Remove(p.skelChunkerPath)
+		}
+	} else {
+		p.Result.Embeddings = p.vectors[:p.fileTexts]
+		for j, i := range p.chunkIndexes {
+			p.Result.Chunks[i].Embeddings = p.vectors[p.fileTexts+j]
+		}
+		a.chunkEmbeddings.addChunks(p.Result.Chunks)
+		a.stats.embedded.Add(int64(len(p.chunkIndexes)))
+	}
+	p.texts = nil
+	p.vectors = nil
+}
@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ResultExtension은 파일별 분석 결과 파일의 확장자입니다. 원본 확장자 뒤에 붙습니다 (Foo.cs.SkelChunker).
+const ResultExtension = ".SkelChunker"
+
+// OutputConfig는 파일별 분석 결과를 저장할 위치입니다.
+type OutputConfig struct {
+	Root    string // 소스 트리를 그대로 옮겨 결과를 저장할 폴더 ("." 이면 소스 파일 옆)
+	BaseDir string // 결과에 기록하는 상대 경로의 기준 폴더 (보통 현재 작업 폴더)
+}
+
+// ConfigureOutput은 결과 저장 위치를 설정합니다. 설정하지 않으면 결과는 소스 파일 옆에 저장됩니다.
+func (a *Analyzer) ConfigureOutput(config OutputConfig) error {
+	if config.Root == "" {
+		config.Root = "."
+	}
+	if config.BaseDir != "" {
+		baseDir, err := filepath.Abs(config.BaseDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve base folder: %w", err)
+		}
+		config.BaseDir = baseDir
+	}
+	a.output = config
+	return nil
+}
+
+// RelativePath는 결과에 기록할 파일 경로를 반환합니다.
+// 기준 폴더 안의 파일은 기준 폴더에 대한 상대 경로를, 밖의 파일은 절대 경로를 슬래시 구분자로 반환합니다.
+func (a *Analyzer) RelativePath(filePath string) string {
+	if a.output.BaseDir == "" {
+		return filepath.ToSlash(filepath.Clean(filePath))
+	}
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return filepath.ToSlash(filepath.Clean(filePath))
+	}
+	relPath, err := filepath.Rel(a.output.BaseDir, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(absPath)
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// ResultPath는 RelativePath로 얻은 파일 경로의 결과 파일 경로를 반환합니다.
+// 결과 폴더는 소스 트리를 그대로 따르며, 기준 폴더 밖의 파일은 결과 폴더의 _external 아래에 절대 경로를 따라 저장합니다.
+func (a *Analyzer) ResultPath(relPath string) string {
+	root := a.output.Root
+	if root == "" {
+		root = "."
+	}
+	localPath := filepath.FromSlash(relPath)
+	if filepath.IsAbs(localPath) {
+		if root == "." {
+			return localPath + ResultExtension
+		}
+		volume := filepath.VolumeName(localPath)
+		localPath = filepath.Join("_external", strings.TrimSuffix(volume, ":"), localPath[len(volume):])
+	}
+	return filepath.Join(root, localPath+ResultExtension)
+}
+
+// legacyResultPath는 이전 버전이 소스 파일 옆에 확장자를 빼고 저장하던 결과 파일 경로(Foo.SkelChunker)입니다.
+// 결과를 새 위치로 옮길 때 이전 임베딩을 재사용하기 위해서만 읽습니다.
+func legacyResultPath(filePath string) string {
+	baseFileName := filepath.Base(filePath)
+	ext := filepath.Ext(baseFileName)
+	return filepath.Join(filepath.Dir(filePath), baseFileName[:len(baseFileName)-len(ext)]+ResultExtension)
+}
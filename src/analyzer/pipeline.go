@@ -0,0 +1,224 @@
+package analyzer
+
+import (
+	"SkelChunker/src/model"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// PipelineConfig는 분석 파이프라인의 단계별 작업자 수입니다. 0 이하이면 기본값을 사용합니다.
+type PipelineConfig struct {
+	DiscoverWorkers int // 동시에 탐색할 폴더 수 (기본값 1)
+	ParseWorkers    int // 동시에 파싱할 파일 수 (기본값 CPU 수)
+	EmbedWorkers    int // 동시에 보낼 임베딩 요청 수 (기본값 4)
+}
+
+// WalkFunc는 폴더 하나에서 분석할 파일을 찾아 찾은 순서대로 visit에 넘깁니다.
+type WalkFunc func(folder string, visit func(path string)) error
+
+// Pipeline은 파일 탐색, 파싱, 임베딩 생성을 단계별 작업자 풀로 동시에 수행합니다.
+// 임베딩은 여러 파일의 텍스트를 배치로 묶어 요청합니다.
+// 결과와 오류는 작업자 수와 관계없이 폴더 순서와 폴더 안의 탐색 순서로 정렬됩니다.
+type Pipeline struct {
+	analyzer   *Analyzer
+	config     PipelineConfig
+	reports    []FileReport
+	walkErrors []error
+}
+
+// FileReport는 파이프라인이 처리한 파일 하나의 결과입니다.
+type FileReport struct {
+	Path     string                // 탐색한 파일 경로
+	Result   *model.AnalysisResult // 실패하면 nil
+	Parser   string
+	Language string
+	Cached   bool // 파일이 변경되지 않아 기존 결과를 재사용함
+	Err      error
+}
+
+// pipelineItem은 파이프라인을 지나는 파일 하나입니다.
+type pipelineItem struct {
+	folder int // 폴더 순서
+	seq    int // 폴더 안의 탐색 순서
+	path   string
+	parsed *ParsedFile
+	err    error
+}
+
+// NewPipeline은 주어진 분석기와 작업자 수로 새로운 파이프라인을 생성합니다.
+func NewPipeline(analyzer *Analyzer, config PipelineConfig) *Pipeline {
+	if config.DiscoverWorkers <= 0 {
+		config.DiscoverWorkers = 1
+	}
+	if config.ParseWorkers <= 0 {
+		config.ParseWorkers = runtime.NumCPU()
+	}
+	if config.EmbedWorkers <= 0 {
+		config.EmbedWorkers = 4
+	}
+	return &Pipeline{analyzer: analyzer, config: config}
+}
+
+// Run은 모든 폴더의 파일을 분석하여 결과와 오류를 반환합니다.
+// 파일 하나의 오류(파서의 panic 포함)는 그 파일만 제외하며, 폴더 탐색 오류도 오류 목록에 포함됩니다.
+func (p *Pipeline) Run(folders []string, walk WalkFunc) ([]*model.AnalysisResult, []error) {
+	discovered := make(chan *pipelineItem, p.config.ParseWorkers*2)
+	parsed := make(chan *pipelineItem, p.config.EmbedWorkers*2)
+	done := make(chan *pipelineItem, p.config.ParseWorkers+p.config.EmbedWorkers)
+
+	// 1단계: 폴더 탐색
+	walkErrors := make([]error, len(folders)) // 폴더 순서별 탐색 오류
+	var discoverers sync.WaitGroup
+	folderQueue := make(chan int)
+	for w := 0; w < p.config.DiscoverWorkers; w++ {
+		discoverers.Add(1)
+		go func() {
+			defer discoverers.Done()
+			for folder := range folderQueue {
+				seq := 0
+				err := walk(folders[folder], func(path string) {
+					discovered <- &pipelineItem{folder: folder, seq: seq, path: path}
+					seq++
+				})
+				if err != nil {
+					walkErrors[folder] = fmt.Errorf("failed to process folder %s: %w", folders[folder], err)
+				}
+			}
+		}()
+	}
+	go func() {
+		for folder := range folders {
+			folderQueue <- folder
+		}
+		close(folderQueue)
+		discoverers.Wait()
+		close(discovered)
+	}()
+
+	// 2단계: 파싱 (임베딩이 필요 없는 파일은 바로 완료)
+	var parsers sync.WaitGroup
+	for w := 0; w < p.config.ParseWorkers; w++ {
+		parsers.Add(1)
+		go func() {
+			defer parsers.Done()
+			for item := range discovered {
+				item.parsed, item.err = p.parseFile(item.path)
+				if item.err == nil && item.parsed.needsEmbedding() {
+					parsed <- item
+				} else {
+					done <- item
+				}
+			}
+		}()
+	}
+	go func() {
+		parsers.Wait()
+		close(parsed)
+	}()
+
+	// 3단계: 임베딩 생성 (파일들의 텍스트를 배치로 묶고, 배치마다 요청 한 번)
+	batches := make(chan *embeddingBatch, p.config.EmbedWorkers)
+	owners := make(map[*ParsedFile]*pipelineItem)
+	var ownersMu sync.Mutex
+	go func() {
+		batcher := p.analyzer.newEmbeddingBatcher()
+		for item := range parsed {
+			ownersMu.Lock()
+			owners[item.parsed] = item
+			ownersMu.Unlock()
+			for _, batch := range batcher.add(item.parsed) {
+				batches <- batch
+			}
+		}
+		if batch := batcher.flush(); batch != nil {
+			batches <- batch
+		}
+		close(batches)
+	}()
+
+	var embedders sync.WaitGroup
+	for w := 0; w < p.config.EmbedWorkers; w++ {
+		embedders.Add(1)
+		go func() {
+			defer embedders.Done()
+			for batch := range batches {
+				// 모든 텍스트의 임베딩을 받은 파일만 완료
+				for _, file := range p.analyzer.embedBatch(batch) {
+					ownersMu.Lock()
+					item := owners[file]
+					ownersMu.Unlock()
+					item.err = file.embedErr
+					done <- item
+				}
+			}
+		}()
+	}
+	go func() {
+		parsers.Wait()
+		embedders.Wait()
+		close(done)
+	}()
+
+	// 결과 수집 후 탐색 순서로 정렬
+	var items []*pipelineItem
+	for item := range done {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].folder != items[j].folder {
+			return items[i].folder < items[j].folder
+		}
+		return items[i].seq < items[j].seq
+	})
+
+	var results []*model.AnalysisResult
+	var errs []error
+	p.walkErrors = nil
+	for _, err := range walkErrors {
+		if err != nil {
+			p.walkErrors = append(p.walkErrors, err)
+			errs = append(errs, err)
+		}
+	}
+	p.reports = make([]FileReport, 0, len(items))
+	for _, item := range items {
+		report := FileReport{Path: item.path, Err: item.err}
+		if item.parsed != nil {
+			report.Parser, report.Language, report.Cached = item.parsed.Parser, item.parsed.Language, item.parsed.Cached
+		} else if fileParser, err := p.analyzer.parserFactory.DetectFile(item.path); err == nil {
+			report.Parser, report.Language = fileParser.GetName(), fileParser.GetLanguage()
+		}
+		if item.err != nil {
+			errs = append(errs, fmt.Errorf("failed to analyze file %s: %w", item.path, item.err))
+		} else {
+			report.Result = item.parsed.Result
+			results = append(results, item.parsed.Result)
+		}
+		p.reports = append(p.reports, report)
+	}
+	return results, errs
+}
+
+// parseFile은 파일 하나를 파싱합니다. 잘못된 파일 하나로 전체 실행이 중단되지 않도록
+// 파서의 panic은 그 파일의 오류로 바꿉니다.
+func (p *Pipeline) parseFile(path string) (parsed *ParsedFile, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			parsed, err = nil, fmt.Errorf("parser panic: %v", r)
+		}
+	}()
+	return p.analyzer.ParseFile(path)
+}
+
+// Reports는 마지막 Run에서 처리한 모든 파일(실패 포함)의 결과를 탐색 순서로 반환합니다.
+func (p *Pipeline) Reports() []FileReport {
+	return p.reports
+}
+
+// WalkErrors는 마지막 Run에서 탐색에 실패한 폴더의 오류를 반환합니다.
+// 탐색에 실패하면 찾지 못한 파일이 있을 수 있으므로, 결과 파일 정리처럼 전체 파일 목록이 필요한 작업은 건너뛰어야 합니다.
+func (p *Pipeline) WalkErrors() []error {
+	return p.walkErrors
+}
@@ -0,0 +1,224 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 오래된 결과 파일의 정리 사유
+const (
+	StaleDeleted = "deleted" // 원본 파일이 없어짐
+	StaleIgnored = "ignored" // 원본 파일은 있지만 더 이상 분석 대상이 아님 (무시 폴더, 파서 없음)
+	StaleRenamed = "renamed" // 원본 파일이 같은 내용으로 다른 경로에 옮겨짐 (임베딩은 새 결과가 이어받음)
+	StaleLegacy  = "legacy"  // 이전 버전이 소스 파일 옆에 저장한 결과
+)
+
+// StaleOutput은 원본이 없어졌거나 더 이상 분석 대상이 아니어서 정리해야 하는 결과 파일입니다.
+type StaleOutput struct {
+	Path      string // 결과 파일 경로
+	Source    string // 결과가 가리키던 원본 파일 (RelativePath 형식, legacy는 빈 문자열)
+	Reason    string
+	RenamedTo string // renamed인 경우 새 원본 파일
+}
+
+// renameTracker는 원본이 없어진 결과 파일을 파일 MD5로 찾아, 같은 내용의 새 파일이 이어받게 합니다.
+type renameTracker struct {
+	mu      sync.Mutex
+	byMD5   map[string]string // 파일 MD5 → 원본이 없어진 결과 파일
+	renamed map[string]string // 결과 파일 → 이어받은 새 원본 파일
+}
+
+// TrackRenames는 결과 폴더에서 원본이 없어진 결과 파일을 찾아 파일 MD5로 기억합니다.
+// 분석 전에 호출하면 옮겨지거나 이름이 바뀐 파일이 다시 임베딩하지 않고 이전 결과를 이어받습니다.
+// 결과를 소스 파일 옆에 저장하는 경우("." 결과 폴더)는 추적하지 않습니다.
+func (a *Analyzer) TrackRenames() error {
+	tracker := &renameTracker{byMD5: make(map[string]string), renamed: make(map[string]string)}
+	if a.output.Root != "." && a.output.Root != "" {
+		outputs, err := ListOutputs(a.output.Root)
+		if err != nil {
+			return err
+		}
+		for _, output := range outputs {
+			source, ok := a.sourceOf(output)
+			if !ok || a.sourceExists(source) {
+				continue
+			}
+			data, err := os.ReadFile(output)
+			if err != nil {
+				continue
+			}
+			var header struct {
+				MD5 string `json:"md5"`
+			}
+			if json.Unmarshal(data, &header) == nil && header.MD5 != "" {
+				tracker.byMD5[header.MD5] = output
+			}
+		}
+	}
+	a.renames = tracker
+	return nil
+}
+
+// renamedResult는 같은 파일 MD5를 가진, 원본이 없어진 결과 파일의 내용을 반환하고 relPath가 이어받았다고 기록합니다.
+// 찾지 못하면 os.ErrNotExist를 반환합니다.
+func (a *Analyzer) renamedResult(md5Hash string, relPath string) ([]byte, error) {
+	if a.renames == nil {
+		return nil, os.ErrNotExist
+	}
+	a.renames.mu.Lock()
+	defer a.renames.mu.Unlock()
+
+	output, exists := a.renames.byMD5[md5Hash]
+	if !exists {
+		return nil, os.ErrNotExist
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		return nil, err
+	}
+	if _, claimed := a.renames.renamed[output]; !claimed {
+		a.renames.renamed[output] = relPath
+	}
+	return data, nil
+}
+
+// ListOutputs는 폴더 아래의 모든 결과 파일 경로를 반환합니다. 폴더가 없으면 빈 목록입니다.
+func ListOutputs(dir string) ([]string, error) {
+	var outputs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ResultExtension) {
+			outputs = append(outputs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outputs in %s: %w", dir, err)
+	}
+	return outputs, nil
+}
+
+// sourceOf는 결과 폴더 안의 결과 파일이 가리키는 원본 파일(RelativePath 형식)을 반환합니다.
+// 결과 폴더 밖의 파일이면 false입니다.
+func (a *Analyzer) sourceOf(outputPath string) (string, bool) {
+	if a.output.Root == "." || a.output.Root == "" {
+		return "", false
+	}
+	root, err := filepath.Abs(a.output.Root)
+	if err != nil {
+		return "", false
+	}
+	absPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return "", false
+	}
+	relPath, err := filepath.Rel(root, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	source := filepath.ToSlash(strings.TrimSuffix(relPath, ResultExtension))
+	if external, ok := strings.CutPrefix(source, "_external/"); ok {
+		// ResultPath의 반대: _external/<드라이브>/<경로> 또는 _external/<경로>
+		if volume, rest, found := strings.Cut(external, "/"); found && filepath.VolumeName(volume+":") != "" {
+			return volume + ":/" + rest, true
+		}
+		return "/" + external, true
+	}
+	return source, true
+}
+
+// sourceExists는 RelativePath 형식의 원본 파일이 있는지 확인합니다.
+func (a *Analyzer) sourceExists(relPath string) bool {
+	localPath := filepath.FromSlash(relPath)
+	if !filepath.IsAbs(localPath) && a.output.BaseDir != "" {
+		localPath = filepath.Join(a.output.BaseDir, localPath)
+	}
+	info, err := os.Stat(localPath)
+	return err == nil && !info.IsDir()
+}
+
+// StaleOutputs는 결과 파일 중 이번에 분석한 파일의 결과가 아닌 것들을 사유와 함께 반환합니다.
+// outputs는 결과 폴더와 소스 폴더에서 찾은 결과 파일, analyzed는 분석 대상으로 찾은 모든 원본 파일입니다.
+func (a *Analyzer) StaleOutputs(outputs []string, analyzed []string) []StaleOutput {
+	live := make(map[string]bool, len(analyzed))
+	for _, filePath := range analyzed {
+		if absPath, err := filepath.Abs(a.ResultPath(a.RelativePath(filePath))); err == nil {
+			live[absPath] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	var stale []StaleOutput
+	for _, output := range outputs {
+		absPath, err := filepath.Abs(output)
+		if err != nil || live[absPath] || seen[absPath] {
+			continue
+		}
+		seen[absPath] = true
+
+		source, inRoot := a.sourceOf(output)
+		if !inRoot {
+			if a.output.Root != "." && a.output.Root != "" {
+				stale = append(stale, StaleOutput{Path: output, Reason: StaleLegacy})
+				continue
+			}
+			source = a.RelativePath(strings.TrimSuffix(output, ResultExtension))
+		}
+
+		item := StaleOutput{Path: output, Source: source, Reason: StaleIgnored}
+		if renamedTo := a.renamedTo(output); renamedTo != "" {
+			item.Reason, item.RenamedTo = StaleRenamed, renamedTo
+		} else if !a.sourceExists(source) {
+			item.Reason = StaleDeleted
+		}
+		stale = append(stale, item)
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Path < stale[j].Path
+	})
+	return stale
+}
+
+// renamedTo는 결과 파일을 이어받은 새 원본 파일을 반환합니다.
+func (a *Analyzer) renamedTo(output string) string {
+	if a.renames == nil {
+		return ""
+	}
+	a.renames.mu.Lock()
+	defer a.renames.mu.Unlock()
+	return a.renames.renamed[output]
+}
+
+// RemoveOutputs는 결과 파일들을 삭제하고, 결과 폴더 안에서 비게 된 하위 폴더도 삭제합니다.
+// 삭제한 결과 파일과 삭제하지 못한 파일의 오류를 반환합니다.
+func (a *Analyzer) RemoveOutputs(stale []StaleOutput) ([]StaleOutput, []error) {
+	var removed []StaleOutput
+	var errs []error
+	for _, item := range stale {
+		if err := os.Remove(item.Path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", item.Path, err))
+			continue
+		}
+		removed = append(removed, item)
+		if _, inRoot := a.sourceOf(item.Path); inRoot {
+			root, _ := filepath.Abs(a.output.Root)
+			dir, _ := filepath.Abs(filepath.Dir(item.Path))
+			for dir != root && strings.HasPrefix(dir, root) && os.Remove(dir) == nil {
+				dir = filepath.Dir(dir)
+			}
+		}
+	}
+	return removed, errs
+}
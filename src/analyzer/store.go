@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"SkelChunker/src/model"
+	"crypto/md5"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+)
+
+// chunkEmbeddingStore는 청크 내용의 MD5로 임베딩을 찾는 저장소입니다 (content-addressed).
+// 이전 SkelChunker 파일의 청크와 이번 실행에서 임베딩한 청크가 모여, 같은 내용의 청크는 파일이 달라도 재사용됩니다.
+type chunkEmbeddingStore struct {
+	mu      sync.RWMutex
+	vectors map[string][]float32
+}
+
+// newChunkEmbeddingStore는 빈 청크 임베딩 저장소를 생성합니다.
+func newChunkEmbeddingStore() *chunkEmbeddingStore {
+	return &chunkEmbeddingStore{vectors: make(map[string][]float32)}
+}
+
+// get은 청크 키에 해당하는 임베딩을 반환합니다.
+func (s *chunkEmbeddingStore) get(key string) ([]float32, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	vector, ok := s.vectors[key]
+	return vector, ok
+}
+
+// addChunks는 임베딩이 있는 청크들을 저장소에 추가합니다.
+func (s *chunkEmbeddingStore) addChunks(chunks []model.Chunk) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, chunk := range chunks {
+		if len(chunk.Embeddings) > 0 {
+			s.vectors[chunkKey(chunk)] = chunk.Embeddings
+		}
+	}
+}
+
+// chunkKey는 청크의 저장소 키를 반환합니다. 파서가 MD5를 채우지 않은 청크는 텍스트로 계산합니다.
+func chunkKey(chunk model.Chunk) string {
+	if chunk.MD5 != "" {
+		return chunk.MD5
+	}
+	hash := md5.Sum([]byte(chunk.Text))
+	return hex.EncodeToString(hash[:])
+}
+
+// embeddingsCompatible은 결과에 저장된 임베딩이 현재 임베딩 설정의 모델과 차원으로 만든 것인지 확인합니다.
+// 모델을 기록하지 않은 이전 결과는 벡터 길이로만 판단합니다.
+func (a *Analyzer) embeddingsCompatible(result *model.AnalysisResult) bool {
+	if a.embeddingConfig == nil {
+		return false
+	}
+	if result.EmbeddingModel != "" && result.EmbeddingModel != a.embeddingConfig.ModelName {
+		return false
+	}
+	if a.embeddingConfig.VectorDim <= 0 {
+		return true
+	}
+	for _, vector := range result.Embeddings {
+		if len(vector) != a.embeddingConfig.VectorDim {
+			return false
+		}
+	}
+	for _, chunk := range result.Chunks {
+		if len(chunk.Embeddings) > 0 && len(chunk.Embeddings) != a.embeddingConfig.VectorDim {
+			return false
+		}
+	}
+	return true
+}
+
+// EmbeddingStats는 이번 실행에서 청크 임베딩을 새로 만들거나 재사용한 횟수입니다.
+type EmbeddingStats struct {
+	Embedded int // 임베딩 API로 새로 만든 청크 수
+	Reused   int // 같은 내용의 청크 임베딩을 재사용한 청크 수
+}
+
+// embeddingCounters는 여러 고루틴에서 갱신하는 EmbeddingStats 카운터입니다.
+type embeddingCounters struct {
+	embedded atomic.Int64
+	reused   atomic.Int64
+}
+
+// EmbeddingStats는 지금까지의 청크 임베딩 통계를 반환합니다.
+func (a *Analyzer) EmbeddingStats() EmbeddingStats {
+	return EmbeddingStats{Embedded: int(a.stats.embedded.Load()), Reused: int(a.stats.reused.Load())}
+}
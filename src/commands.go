@@ -0,0 +1,384 @@
+package main
+
+import (
+	"SkelChunker/src/analyzer"
+	"SkelChunker/src/config"
+	"SkelChunker/src/embeddings"
+	"SkelChunker/src/project"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// parseQueryArgs는 조회 명령의 -config 플래그와 심볼 인자를 읽고 설정을 로드합니다.
+// flags가 nil이 아니면 명령별 플래그를 미리 정의한 플래그 집합을 사용합니다.
+// 플래그는 심볼 앞뒤 어디에 있어도 되며(hierarchy Foo -json), 심볼 외의 인자가 남으면 오류를 반환합니다.
+func parseQueryArgs(name string, args []string, flags *flag.FlagSet) (*config.Config, string, error) {
+	if flags == nil {
+		flags = flag.NewFlagSet(name, flag.ExitOnError)
+	}
+	configPath := flags.String("config", "config.json", "Path to configuration file")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		return nil, "", fmt.Errorf("usage: skelchunker %s [-config config.json] <symbol>", name)
+	}
+
+	// flag 패키지는 첫 번째 위치 인자에서 멈추므로 심볼 뒤의 플래그를 다시 읽음
+	symbol := flags.Arg(0)
+	flags.Parse(flags.Args()[1:])
+	if flags.NArg() != 0 {
+		return nil, "", fmt.Errorf("unexpected arguments after %s: %s (usage: skelchunker %s [-config config.json] <symbol>)", symbol, strings.Join(flags.Args(), " "), name)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config: %w", err)
+	}
+	return cfg, symbol, nil
+}
+
+// loadSymbolIndex는 index 명령이 저장한 심볼 색인을 읽습니다.
+func loadSymbolIndex(cfg *config.Config) (*project.SymbolIndex, error) {
+	var index project.SymbolIndex
+	if err := project.ReadJSON(cfg.ProjectOutput, project.SymbolsFileName, &index); err != nil {
+		return nil, fmt.Errorf("%w (run skelchunker index first)", err)
+	}
+	return &index, nil
+}
+
+// runDefinition은 심볼이 선언된 위치를 출력합니다. 찾지 못하면 1을 반환합니다.
+func runDefinition(args []string) int {
+	cfg, symbol, err := parseQueryArgs("def", args, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	index, err := loadSymbolIndex(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading symbol index: %v\n", err)
+		return 2
+	}
+
+	definitions := index.Definitions(symbol)
+	if len(definitions) == 0 {
+		fmt.Fprintf(os.Stderr, "No definition found for %s\n", symbol)
+		return 1
+	}
+	for _, definition := range definitions {
+		location := definition.File
+		if definition.Range != nil {
+			location = fmt.Sprintf("%s:%d-%d", definition.File, definition.Range.StartLine, definition.Range.EndLine)
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\n", location, definition.Type, definition.QualifiedName, definition.Signature)
+	}
+	return 0
+}
+
+// runReferences는 심볼 이름이 나타난 위치와 그 라인을 출력합니다. 찾지 못하면 1을 반환합니다.
+func runReferences(args []string) int {
+	cfg, symbol, err := parseQueryArgs("refs", args, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	index, err := loadSymbolIndex(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading symbol index: %v\n", err)
+		return 2
+	}
+
+	references := index.FindReferences(symbol)
+	if len(references) == 0 {
+		fmt.Fprintf(os.Stderr, "No references found for %s\n", symbol)
+		return 1
+	}
+
+	// 라인 내용은 원본 파일에서 읽음 (파일이 바뀌었거나 없으면 위치만 출력)
+	lines := make(map[string][]string)
+	for _, reference := range references {
+		fileLines, exists := lines[reference.File]
+		if !exists {
+			if content, err := os.ReadFile(reference.File); err == nil {
+				fileLines = strings.Split(string(content), "\n")
+			}
+			lines[reference.File] = fileLines
+		}
+
+		text := ""
+		if reference.Line <= len(fileLines) {
+			text = strings.TrimSpace(fileLines[reference.Line-1])
+		}
+		fmt.Printf("%s:%d:%d\t%s\n", reference.File, reference.Line, reference.Column, text)
+	}
+	return 0
+}
+
+// hierarchyAnswer는 hierarchy 명령의 JSON 출력입니다.
+type hierarchyAnswer struct {
+	Type         project.TypeHierarchyNode   `json:"type"`
+	Ancestors    []project.TypeHierarchyNode `json:"ancestors"`
+	Descendants  []project.TypeHierarchyNode `json:"descendants"`
+	Implementers []project.TypeHierarchyNode `json:"implementers"`
+}
+
+// runHierarchy는 타입의 상위 타입, 하위 타입, 구현체를 출력합니다. 찾지 못하면 1을 반환합니다.
+func runHierarchy(args []string) int {
+	flags := flag.NewFlagSet("hierarchy", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Print the result as JSON")
+	cfg, typeName, err := parseQueryArgs("hierarchy", args, flags)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	var hierarchy project.TypeHierarchy
+	if err := project.ReadJSON(cfg.ProjectOutput, project.HierarchyFileName, &hierarchy); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading type hierarchy: %v (run skelchunker index first)\n", err)
+		return 2
+	}
+
+	types := hierarchy.Find(typeName)
+	if len(types) == 0 {
+		fmt.Fprintf(os.Stderr, "No type found for %s\n", typeName)
+		return 1
+	}
+
+	answers := make([]hierarchyAnswer, 0, len(types))
+	for _, node := range types {
+		answers = append(answers, hierarchyAnswer{
+			Type:         node,
+			Ancestors:    hierarchy.Ancestors(node.Name),
+			Descendants:  hierarchy.Descendants(node.Name),
+			Implementers: hierarchy.Implementers(node.Name),
+		})
+	}
+	if *asJSON {
+		data, err := json.MarshalIndent(answers, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding type hierarchy: %v\n", err)
+			return 2
+		}
+		fmt.Println(string(data))
+		return 0
+	}
+
+	for i, answer := range answers {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(describeType(answer.Type))
+		printTypeList("ancestors", answer.Ancestors)
+		printTypeList("descendants", answer.Descendants)
+		if answer.Type.Kind == "interface" || answer.Type.External {
+			printTypeList("implementers", answer.Implementers)
+		}
+	}
+	return 0
+}
+
+// printTypeList는 제목과 타입 목록을 들여 써서 출력합니다.
+func printTypeList(title string, types []project.TypeHierarchyNode) {
+	fmt.Printf("%s:\n", title)
+	if len(types) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, node := range types {
+		fmt.Printf("  %s\n", describeType(node))
+	}
+}
+
+// describeType은 타입 이름, 종류, 선언 파일을 한 줄로 만듭니다.
+func describeType(node project.TypeHierarchyNode) string {
+	if node.External {
+		return node.Name + " (external)"
+	}
+	return fmt.Sprintf("%s (%s) %s", node.Name, node.Kind, strings.Join(node.Files, ", "))
+}
+
+// runDiagram은 분석 결과로 클래스, 패키지 의존, 호출 그래프 다이어그램을 DOT 또는 Mermaid로 출력합니다.
+func runDiagram(args []string) int {
+	flags := flag.NewFlagSet("diagram", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Path to configuration file")
+	diagramType := flags.String("type", project.DiagramClasses, "Diagram type: classes, packages or calls")
+	format := flags.String("format", "mermaid", "Output format: dot or mermaid")
+	by := flags.String("by", "folder", "Group packages by folder or namespace")
+	output := flags.String("o", "", "Write the diagram to a file instead of stdout")
+	var scope project.DiagramScope
+	flags.StringVar(&scope.Folder, "folder", "", "Only include elements declared under this folder")
+	flags.StringVar(&scope.Namespace, "namespace", "", "Only include elements in this namespace")
+	flags.StringVar(&scope.Root, "root", "", "Only include elements reachable from this type, method or package")
+	flags.IntVar(&scope.Depth, "depth", 0, "Maximum depth to follow from -root (0 for unlimited)")
+	flags.Parse(args)
+
+	if *format != "dot" && *format != "mermaid" {
+		fmt.Fprintf(os.Stderr, "Unknown diagram format: %s\n", *format)
+		return 2
+	}
+	if *by != "folder" && *by != "namespace" {
+		fmt.Fprintf(os.Stderr, "Unknown package grouping: %s\n", *by)
+		return 2
+	}
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 2
+	}
+
+	var diagram *project.Diagram
+	switch *diagramType {
+	case project.DiagramClasses:
+		var hierarchy project.TypeHierarchy
+		var symbols project.SymbolIndex
+		err = project.ReadJSON(cfg.ProjectOutput, project.HierarchyFileName, &hierarchy)
+		if err == nil {
+			err = project.ReadJSON(cfg.ProjectOutput, project.SymbolsFileName, &symbols)
+		}
+		diagram = project.ClassDiagram(&hierarchy, &symbols, scope)
+	case project.DiagramPackages:
+		var dependencies project.DependencyGraph
+		err = project.ReadJSON(cfg.ProjectOutput, project.DependenciesFileName, &dependencies)
+		diagram = project.PackageDiagram(&dependencies, *by == "namespace", scope)
+	case project.DiagramCalls:
+		var callGraph project.CallGraph
+		err = project.ReadJSON(cfg.ProjectOutput, project.CallGraphFileName, &callGraph)
+		diagram = project.CallDiagram(&callGraph, scope)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown diagram type: %s\n", *diagramType)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading analysis results: %v (run skelchunker index first)\n", err)
+		return 2
+	}
+
+	text := diagram.Mermaid()
+	if *format == "dot" {
+		text = diagram.DOT()
+	}
+	if *output == "" {
+		fmt.Print(text)
+		return 0
+	}
+	if err := os.WriteFile(*output, []byte(text), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing diagram: %v\n", err)
+		return 2
+	}
+	fmt.Printf("Wrote %s diagram with %d nodes to %s\n", *diagramType, len(diagram.Nodes), *output)
+	return 0
+}
+
+// cacheDir는 설정의 임베딩 캐시 폴더를 반환합니다. 설정하지 않았으면 기본 폴더를 사용합니다.
+func cacheDir(cfg *config.Config) string {
+	if cfg.Embedding.Cache.Dir != "" {
+		return cfg.Embedding.Cache.Dir
+	}
+	return embeddings.DefaultCacheDir()
+}
+
+// runCache는 디스크 임베딩 캐시의 크기를 출력하거나(stats) 오래 쓰지 않은 항목을 정리합니다(prune).
+func runCache(args []string) int {
+	if len(args) == 0 || (args[0] != "stats" && args[0] != "prune") {
+		fmt.Fprintln(os.Stderr, "usage: skelchunker cache stats|prune [-config config.json] [-max-size-mb n] [-max-age-days n]")
+		return 2
+	}
+	action := args[0]
+	flags := flag.NewFlagSet("cache "+action, flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Path to configuration file")
+	maxSizeMB := flags.Int("max-size-mb", -1, "Maximum cache size in MB (default: embedding.cache.max-size-mb)")
+	maxAgeDays := flags.Int("max-age-days", -1, "Remove entries unused for this many days (default: embedding.cache.max-age-days)")
+	flags.Parse(args[1:])
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 2
+	}
+	dir := cacheDir(cfg)
+
+	if action == "prune" {
+		if *maxSizeMB < 0 {
+			*maxSizeMB = cfg.Embedding.Cache.MaxSizeMB
+		}
+		if *maxAgeDays < 0 {
+			*maxAgeDays = cfg.Embedding.Cache.MaxAgeDays
+		}
+		pruned, err := embeddings.PruneCache(dir, int64(*maxSizeMB)*1024*1024, time.Duration(*maxAgeDays)*24*time.Hour)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error pruning embedding cache: %v\n", err)
+			return 2
+		}
+		fmt.Printf("Removed %d entries (%d bytes) from %s\n", pruned.Entries, pruned.Bytes, dir)
+	}
+
+	usage, err := embeddings.CacheStats(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading embedding cache: %v\n", err)
+		return 2
+	}
+	fmt.Printf("%s: %d entries, %d bytes\n", dir, usage.Entries, usage.Bytes)
+	if usage.Entries > 0 {
+		fmt.Printf("last used: %s (oldest) - %s (newest)\n", usage.Oldest.Format(time.RFC3339), usage.Newest.Format(time.RFC3339))
+	}
+	return 0
+}
+
+// runClean은 원본이 없어졌거나 더 이상 분석 대상이 아닌 결과 파일을 찾아 삭제합니다. -n이면 목록만 출력합니다.
+func runClean(args []string) int {
+	flags := flag.NewFlagSet("clean", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Path to configuration file")
+	dryRun := flags.Bool("n", false, "Only list stale outputs without removing them")
+	flags.Parse(args)
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 2
+	}
+	parserFactory, err := newParserFactory(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in %v\n", err)
+		return 2
+	}
+	fileAnalyzer := analyzer.NewAnalyzer(parserFactory, nil, nil)
+	if err := configureOutput(fileAnalyzer, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in output configuration: %v\n", err)
+		return 2
+	}
+
+	// 파싱 없이 분석 대상 파일만 찾음
+	walker, err := newSourceWalker(cfg, parserFactory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in ignore configuration: %v\n", err)
+		return 2
+	}
+	for _, folder := range cfg.Folders {
+		if err := walker.walk(folder, func(path string) {}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error processing folder %s: %v\n", folder, err)
+			return 2
+		}
+	}
+	stale, err := walker.staleOutputs(fileAnalyzer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding stale outputs: %v\n", err)
+		return 2
+	}
+
+	if *dryRun {
+		for _, item := range stale {
+			fmt.Printf("Would remove %s\n", describeStaleOutput(item))
+		}
+		return 0
+	}
+	status := 0
+	removed, errs := fileAnalyzer.RemoveOutputs(stale)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		status = 1
+	}
+	for _, item := range removed {
+		fmt.Printf("Removed %s\n", describeStaleOutput(item))
+	}
+	return status
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseQueryArgs(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeFile(t, configPath, `{"project-output": "out"}`)
+
+	// 플래그는 심볼 앞뒤 어디에 있어도 적용
+	for _, args := range [][]string{
+		{"-json", "-config", configPath, "Foo"},
+		{"Foo", "-json", "-config", configPath},
+		{"-config", configPath, "Foo", "-json"},
+	} {
+		flags := flag.NewFlagSet("hierarchy", flag.ContinueOnError)
+		asJSON := flags.Bool("json", false, "")
+		cfg, symbol, err := parseQueryArgs("hierarchy", args, flags)
+		if err != nil {
+			t.Errorf("%v: 인자 오류: %v", args, err)
+			continue
+		}
+		if symbol != "Foo" || !*asJSON || cfg.ProjectOutput != "out" {
+			t.Errorf("%v: 플래그가 적용되지 않았습니다: symbol=%s json=%v output=%s", args, symbol, *asJSON, cfg.ProjectOutput)
+		}
+	}
+
+	// 심볼이 없거나 심볼 외의 인자가 남으면 오류
+	if _, _, err := parseQueryArgs("def", []string{"-config", configPath}, flag.NewFlagSet("def", flag.ContinueOnError)); err == nil {
+		t.Error("심볼 없이 실행되었습니다")
+	}
+	_, _, err := parseQueryArgs("refs", []string{"Foo", "Bar", "-config", configPath}, flag.NewFlagSet("refs", flag.ContinueOnError))
+	if err == nil || !strings.Contains(err.Error(), "Bar") {
+		t.Errorf("남은 인자가 거부되지 않았습니다: %v", err)
+	}
+}
@@ -3,23 +3,110 @@ package config
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 )
 
 // Config는 애플리케이션의 설정을 담는 구조체입니다.
 type Config struct {
-	Folders       []string          `json:"folders"`
-	IgnoreFolders []string          `json:"ignore-folders"`
-	Parsers       map[string]string `json:"parsers"`
-	Embedding     EmbeddingConfig   `json:"embedding"`
+	Folders           []string           `json:"folders"`
+	IgnoreFolders     []string           `json:"ignore-folders"`
+	IgnoreFiles       []string           `json:"ignore-files"` // 폴더마다 읽을 gitignore 문법의 무시 파일 (기본값 .gitignore, .skelchunkerignore)
+	Include           []string           `json:"include"`      // 분석할 파일과 폴더의 glob 패턴 (gitignore 문법, 비어 있으면 모든 파일)
+	Exclude           []string           `json:"exclude"`      // 제외할 파일과 폴더의 glob 패턴 (gitignore 문법)
+	Parsers           map[string]string  `json:"parsers"`
+	Embedding         EmbeddingConfig    `json:"embedding"`
+	ProjectOutput     string             `json:"project-output"`
+	OutputRoot        string             `json:"output-root"` // 파일별 결과를 소스 트리 구조대로 저장할 폴더 ("."이면 소스 파일 옆)
+	Detection         DetectionConfig    `json:"detection"`
+	Plugins           []PluginConfig     `json:"plugins"`
+	RuleParsers       []RuleParserConfig `json:"rule-parsers"`
+	TreeSitterQueries map[string]string  `json:"tree-sitter-queries"`
+	Workers           WorkersConfig      `json:"workers"`
+}
+
+// WorkersConfig는 분석 파이프라인의 단계별 작업자 수를 담는 구조체입니다. 0이면 기본값을 사용합니다.
+type WorkersConfig struct {
+	Discover int `json:"discover"` // 동시에 탐색할 폴더 수 (기본값 1)
+	Parse    int `json:"parse"`    // 동시에 파싱할 파일 수 (기본값 CPU 수)
+	Embed    int `json:"embed"`    // 동시에 보낼 임베딩 요청 수 (기본값 4)
 }
 
 // EmbeddingConfig는 임베딩 관련 설정을 담는 구조체입니다.
 type EmbeddingConfig struct {
+	Enabled     bool                 `json:"enabled"`
+	APIKey      string               `json:"api-key"`
+	ModelName   string               `json:"model-name"`
+	VectorDim   int                  `json:"vector-dim"`
+	MaxTextSize int                  `json:"max-text-size"`
+	BatchSize   int                  `json:"batch-size"`   // 요청 한 번에 보낼 최대 텍스트 수
+	BatchTokens int                  `json:"batch-tokens"` // 요청 한 번에 보낼 최대 토큰 수 (추정치)
+	Cache       EmbeddingCacheConfig `json:"cache"`
+}
+
+// EmbeddingCacheConfig는 여러 저장소가 함께 쓰는 디스크 임베딩 캐시 설정을 담는 구조체입니다.
+type EmbeddingCacheConfig struct {
 	Enabled    bool   `json:"enabled"`
-	APIKey     string `json:"api-key"`
-	ModelName  string `json:"model-name"`
-	VectorDim  int    `json:"vector-dim"`
-	MaxTextSize int   `json:"max-text-size"`
+	Dir        string `json:"dir"`          // 캐시 폴더 (기본값: 사용자 캐시 폴더의 skelchunker/embeddings)
+	MaxSizeMB  int    `json:"max-size-mb"`  // 캐시 최대 크기, 넘으면 오래 쓰지 않은 항목부터 삭제 (기본값 1024)
+	MaxAgeDays int    `json:"max-age-days"` // 이 기간 동안 쓰지 않은 항목 삭제 (0이면 제한 없음)
+}
+
+// DetectionConfig는 확장자가 없거나 모호한 파일의 파서 판별 규칙을 담는 구조체입니다.
+// 각 값은 등록된 파서 이름입니다.
+type DetectionConfig struct {
+	Filenames map[string]string   `json:"filenames"`
+	Shebangs  map[string]string   `json:"shebangs"`
+	Modelines map[string]string   `json:"modelines"`
+	Content   []ContentRuleConfig `json:"content"`
+}
+
+// ContentRuleConfig는 파일 내용 정규식으로 파서를 선택하는 규칙입니다.
+type ContentRuleConfig struct {
+	Extensions []string `json:"extensions"`
+	Pattern    string   `json:"pattern"`
+	Parser     string   `json:"parser"`
+}
+
+// PluginConfig는 stdin/stdout JSON 프로토콜로 동작하는 외부 파서 플러그인 설정입니다.
+type PluginConfig struct {
+	Name           string   `json:"name"`
+	Language       string   `json:"language"`
+	Command        string   `json:"command"`
+	Args           []string `json:"args"`
+	Extensions     []string `json:"extensions"`
+	TimeoutSeconds int      `json:"timeout-seconds"`
+}
+
+// RuleParserConfig는 설정만으로 정의하는 규칙 기반 파서 설정입니다.
+type RuleParserConfig struct {
+	Name            string             `json:"name"`
+	Language        string             `json:"language"`
+	Extensions      []string           `json:"extensions"`
+	Block           string             `json:"block"`
+	Braces          [2]string          `json:"braces"`
+	CaseInsensitive bool               `json:"case-insensitive"`
+	LineComments    []string           `json:"line-comments"`
+	BlockComments   [][2]string        `json:"block-comments"`
+	Strings         []string           `json:"strings"`
+	StringEscape    string             `json:"string-escape"`
+	Rules           []RuleConfig       `json:"rules"`
+	Imports         []ImportRuleConfig `json:"imports"`
+}
+
+// RuleConfig는 규칙 기반 파서에서 선언 하나를 찾는 규칙입니다.
+type RuleConfig struct {
+	Type      string `json:"type"`
+	Kind      string `json:"kind"`
+	Pattern   string `json:"pattern"`
+	End       string `json:"end"`
+	Block     string `json:"block"`
+	Container bool   `json:"container"`
+}
+
+// ImportRuleConfig는 규칙 기반 파서에서 의존 구문(#include 등)을 찾는 규칙입니다.
+type ImportRuleConfig struct {
+	Kind    string `json:"kind"`
+	Pattern string `json:"pattern"`
 }
 
 // LoadConfig는 지정된 경로의 JSON 파일에서 설정을 로드합니다.
@@ -47,5 +134,32 @@ func LoadConfig(path string) (*Config, error) {
 		config.Embedding.MaxTextSize = 24 * 1024 // 24KB 제한
 	}
 
+	if config.Embedding.BatchSize == 0 {
+		config.Embedding.BatchSize = 512
+	}
+
+	if config.Embedding.BatchTokens == 0 {
+		config.Embedding.BatchTokens = 100000
+	}
+
+	if config.Embedding.Cache.MaxSizeMB == 0 {
+		config.Embedding.Cache.MaxSizeMB = 1024
+	}
+
+	// 무시 파일 기본값 설정 (빈 목록을 지정하면 무시 파일을 읽지 않음)
+	if config.IgnoreFiles == nil {
+		config.IgnoreFiles = []string{".gitignore", ".skelchunkerignore"}
+	}
+
+	// 프로젝트 단위 결과 저장 폴더 기본값 설정
+	if config.ProjectOutput == "" {
+		config.ProjectOutput = ".skelchunker"
+	}
+
+	// 파일별 결과 저장 폴더 기본값 설정 (프로젝트 단위 결과 폴더 아래)
+	if config.OutputRoot == "" {
+		config.OutputRoot = filepath.Join(config.ProjectOutput, "files")
+	}
+
 	return &config, nil
 } 
\ No newline at end of file
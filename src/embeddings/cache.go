@@ -0,0 +1,262 @@
+package embeddings
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Cache는 여러 저장소와 실행이 함께 쓰는 디스크 임베딩 캐시입니다.
+// 항목은 (모델, 차원, 전처리 버전, 텍스트 해시)로 찾으며, 파일 하나에 float32 벡터 하나를 저장합니다.
+// 항목을 읽을 때마다 수정 시각을 갱신하므로 PruneCache는 가장 오래 쓰지 않은 항목부터 지웁니다.
+type Cache struct {
+	dir     string
+	prefix  string // 모델, 차원, 전처리 버전으로 만든 키 접두사
+	hits    atomic.Int64
+	misses  atomic.Int64
+	written atomic.Int64
+}
+
+// CacheUsage는 캐시 폴더의 항목 수와 크기입니다.
+type CacheUsage struct {
+	Entries int
+	Bytes   int64
+	Oldest  time.Time // 가장 오래 쓰지 않은 항목의 마지막 사용 시각
+	Newest  time.Time
+}
+
+// NewCache는 주어진 폴더에 모델, 차원, 전처리 버전별 임베딩 캐시를 엽니다.
+func NewCache(dir string, modelName string, vectorDim int, preprocessingVersion int) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create embedding cache folder: %w", err)
+	}
+	return &Cache{
+		dir:    dir,
+		prefix: fmt.Sprintf("%s\x00%d\x00%d\x00", modelName, vectorDim, preprocessingVersion),
+	}, nil
+}
+
+// DefaultCacheDir는 사용자 캐시 폴더 아래의 기본 임베딩 캐시 폴더를 반환합니다.
+func DefaultCacheDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "skelchunker", "embeddings")
+}
+
+// path는 텍스트의 캐시 항목 파일 경로를 반환합니다. 폴더 하나에 파일이 몰리지 않도록 키 앞 두 글자로 나눕니다.
+func (c *Cache) path(text string) string {
+	textHash := sha256.Sum256([]byte(text))
+	key := sha256.Sum256([]byte(c.prefix + hex.EncodeToString(textHash[:])))
+	name := hex.EncodeToString(key[:])
+	return filepath.Join(c.dir, name[:2], name)
+}
+
+// Get은 텍스트의 임베딩을 캐시에서 찾습니다. 손상된 항목은 없는 것으로 처리합니다.
+func (c *Cache) Get(text string) ([]float32, bool) {
+	path := c.path(text)
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 || len(data)%4 != 0 {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	c.hits.Add(1)
+	return vector, true
+}
+
+// Put은 텍스트의 임베딩을 캐시에 저장합니다.
+// 다른 프로세스가 같은 항목을 동시에 쓸 수 있으므로 임시 파일에 쓴 뒤 이름을 바꿉니다.
+func (c *Cache) Put(text string, vector []float32) error {
+	path := c.path(text)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create embedding cache folder: %w", err)
+	}
+
+	data := make([]byte, len(vector)*4)
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(value))
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write embedding cache entry: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write embedding cache entry: %w", err)
+	}
+	c.written.Add(1)
+	return nil
+}
+
+// Hits는 이번 실행에서 캐시에서 찾은 임베딩 수입니다.
+func (c *Cache) Hits() int { return int(c.hits.Load()) }
+
+// Misses는 이번 실행에서 캐시에 없어 임베딩 서비스에 요청한 텍스트 수입니다.
+func (c *Cache) Misses() int { return int(c.misses.Load()) }
+
+// Written은 이번 실행에서 캐시에 저장한 임베딩 수입니다.
+func (c *Cache) Written() int { return int(c.written.Load()) }
+
+// cacheEntry는 캐시 폴더의 항목 파일 하나입니다.
+type cacheEntry struct {
+	path    string
+	size    int64
+	lastUse time.Time
+}
+
+// scanCache는 캐시 폴더의 모든 항목을 마지막 사용 시각 순서로 반환합니다.
+func scanCache(dir string) ([]cacheEntry, error) {
+	var entries []cacheEntry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // 다른 프로세스가 지운 항목
+		}
+		entries = append(entries, cacheEntry{path: path, size: info.Size(), lastUse: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan embedding cache: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUse.Before(entries[j].lastUse)
+	})
+	return entries, nil
+}
+
+// usageOf는 항목 목록의 항목 수와 크기를 계산합니다.
+func usageOf(entries []cacheEntry) CacheUsage {
+	usage := CacheUsage{Entries: len(entries)}
+	for _, entry := range entries {
+		usage.Bytes += entry.size
+	}
+	if len(entries) > 0 {
+		usage.Oldest = entries[0].lastUse
+		usage.Newest = entries[len(entries)-1].lastUse
+	}
+	return usage
+}
+
+// CacheStats는 캐시 폴더의 항목 수와 크기를 반환합니다.
+func CacheStats(dir string) (CacheUsage, error) {
+	entries, err := scanCache(dir)
+	if err != nil {
+		return CacheUsage{}, err
+	}
+	return usageOf(entries), nil
+}
+
+// PruneCache는 maxAge보다 오래 쓰지 않은 항목을 지우고, 남은 크기가 maxBytes를 넘으면
+// 가장 오래 쓰지 않은 항목부터 지웁니다. 0 이하인 제한은 적용하지 않습니다. 지운 항목의 합계를 반환합니다.
+func PruneCache(dir string, maxBytes int64, maxAge time.Duration) (CacheUsage, error) {
+	entries, err := scanCache(dir)
+	if err != nil {
+		return CacheUsage{}, err
+	}
+
+	total := usageOf(entries).Bytes
+	cutoff := time.Now().Add(-maxAge)
+	var removed []cacheEntry
+	for _, entry := range entries {
+		expired := maxAge > 0 && entry.lastUse.Before(cutoff)
+		if !expired && (maxBytes <= 0 || total <= maxBytes) {
+			break
+		}
+		if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+			return usageOf(removed), fmt.Errorf("failed to remove embedding cache entry: %w", err)
+		}
+		total -= entry.size
+		removed = append(removed, entry)
+	}
+	return usageOf(removed), nil
+}
+
+// CachedEmbedding은 임베딩 서비스를 호출하기 전에 디스크 캐시를 확인하는 EmbeddingService입니다.
+// 캐시에 없는 텍스트만 서비스에 요청하고, 받은 임베딩은 캐시에 저장합니다.
+type CachedEmbedding struct {
+	service EmbeddingService
+	cache   *Cache
+}
+
+// NewCachedEmbedding은 서비스 앞에 캐시를 둔 새로운 CachedEmbedding을 생성합니다.
+func NewCachedEmbedding(service EmbeddingService, cache *Cache) *CachedEmbedding {
+	return &CachedEmbedding{service: service, cache: cache}
+}
+
+// CreateEmbedding은 주어진 텍스트에 대한 임베딩을 캐시 또는 서비스에서 가져옵니다.
+func (e *CachedEmbedding) CreateEmbedding(text string) ([]float32, error) {
+	embeddings, err := e.CreateEmbeddings([]string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// CreateEmbeddings는 캐시에 없는 텍스트만 요청 한 번으로 임베딩하여 입력 순서대로 반환합니다.
+func (e *CachedEmbedding) CreateEmbeddings(texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	var missing []string
+	var missingIndexes []int
+	for i, text := range texts {
+		if vector, ok := e.cache.Get(text); ok {
+			embeddings[i] = vector
+			continue
+		}
+		missing = append(missing, text)
+		missingIndexes = append(missingIndexes, i)
+	}
+	if len(missing) == 0 {
+		return embeddings, nil
+	}
+
+	created, err := e.service.CreateEmbeddings(missing)
+	if err != nil {
+		return nil, err
+	}
+	if len(created) != len(missing) {
+		return nil, fmt.Errorf("embedding service returned %d embeddings for %d texts", len(created), len(missing))
+	}
+	for j, i := range missingIndexes {
+		embeddings[i] = created[j]
+		// 캐시 저장 실패는 임베딩 결과에 영향이 없으므로 무시 (다음 실행에서 다시 요청)
+		e.cache.Put(missing[j], created[j])
+	}
+	return embeddings, nil
+}
+
+// ChunkText는 감싼 서비스의 분할 방식을 그대로 사용합니다.
+func (e *CachedEmbedding) ChunkText(text string, maxSize int) ([]string, error) {
+	return e.service.ChunkText(text, maxSize)
+}
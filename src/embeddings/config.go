@@ -6,6 +6,8 @@ type Config struct {
 	ModelName  string
 	VectorDim  int
 	MaxTextSize int
+	BatchSize   int // 요청 한 번에 보낼 최대 텍스트 수
+	BatchTokens int // 요청 한 번에 보낼 최대 토큰 수 (EstimateTokens 기준)
 }
 
 // DefaultConfig는 기본 설정값으로 Config 인스턴스를 반환합니다.
@@ -15,5 +17,7 @@ func DefaultConfig() *Config {
 		ModelName:  "text-embedding-3-large",
 		VectorDim:  3072,
 		MaxTextSize: 24 * 1024, // 24KB 제한
+		BatchSize:   512,
+		BatchTokens: 100000, // API 제한(요청당 300,000 토큰)보다 추정 오차만큼 여유를 둠
 	}
 } 
\ No newline at end of file
@@ -12,6 +12,7 @@ import (
 // EmbeddingService는 텍스트 임베딩을 생성하는 서비스 인터페이스입니다.
 type EmbeddingService interface {
 	CreateEmbedding(text string) ([]float32, error)
+	CreateEmbeddings(texts []string) ([][]float32, error) // 여러 텍스트를 요청 한 번으로 임베딩 (입력 순서대로 반환)
 	ChunkText(text string, maxSize int) ([]string, error)
 }
 
@@ -73,6 +74,45 @@ func (e *OpenAIEmbedding) CreateEmbedding(text string) ([]float32, error) {
 	return resp.Data[0].Embedding, nil
 }
 
+// CreateEmbeddings는 여러 텍스트의 임베딩을 요청 한 번으로 생성하여 입력 순서대로 반환합니다.
+func (e *OpenAIEmbedding) CreateEmbeddings(texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	embeddingReq := openai.EmbeddingRequest{
+		Input: texts,
+		Model: e.modelName,
+	}
+
+	ctx := context.Background()
+	resp, err := e.client.CreateEmbeddings(ctx, embeddingReq)
+	if err != nil {
+		return nil, fmt.Errorf("임베딩 생성 오류: %w", err)
+	}
+
+	// 응답 순서는 보장되지 않으므로 Index로 입력 위치를 찾음
+	embeddings := make([][]float32, len(texts))
+	for _, data := range resp.Data {
+		if data.Index < 0 || data.Index >= len(texts) {
+			return nil, fmt.Errorf("OpenAI API가 잘못된 임베딩 위치를 반환했습니다: %d", data.Index)
+		}
+		embeddings[data.Index] = data.Embedding
+	}
+	for i, embedding := range embeddings {
+		if embedding == nil {
+			return nil, fmt.Errorf("OpenAI API에서 %d번째 텍스트의 임베딩을 반환하지 않았습니다", i)
+		}
+	}
+
+	return embeddings, nil
+}
+
+// EstimateTokens는 텍스트의 토큰 수를 추정합니다. 코드는 대략 3바이트당 1토큰이므로 여유 있게 계산합니다.
+func EstimateTokens(text string) int {
+	return len(text)/3 + 1
+}
+
 // ChunkText는 텍스트를 최대 크기 제한에 맞게 여러 청크로 분할합니다.
 func (e *OpenAIEmbedding) ChunkText(text string, maxSize int) ([]string, error) {
 	if len(text) <= maxSize {
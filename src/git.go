@@ -0,0 +1,161 @@
+package main
+
+import (
+	"SkelChunker/src/project"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// gitChanges는 커밋 이후 작업 트리에서 바뀐 파일들입니다. 경로는 현재 작업 폴더 기준입니다.
+type gitChanges struct {
+	Changed []string // 추가 또는 수정된 파일 (추적하지 않는 새 파일 포함)
+	Removed []string // 삭제되었거나 다른 이름으로 옮겨진 파일
+}
+
+// runGit은 git 명령을 실행하여 표준 출력을 반환합니다. 실패하면 git의 오류 메시지를 담은 오류를 반환합니다.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// gitHead는 현재 작업 폴더가 속한 저장소의 HEAD 커밋 해시를 반환합니다.
+func gitHead() (string, error) {
+	out, err := runGit(".", "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// gitChangesSince는 커밋과 현재 작업 트리(커밋하지 않은 변경 포함)를 비교하여 바뀐 파일을 찾습니다.
+// .gitignore로 제외되지 않은, 추적하지 않는 새 파일도 추가된 파일로 포함합니다.
+func gitChangesSince(commit string) (*gitChanges, error) {
+	out, err := runGit(".", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	top := strings.TrimSpace(out)
+	if _, err := runGit(top, "rev-parse", "--verify", "--quiet", commit+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown commit: %s", commit)
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	// git은 저장소 최상위 폴더 기준 경로를 출력하므로 현재 작업 폴더 기준으로 바꿈
+	localPath := func(gitPath string) string {
+		absPath := filepath.Join(top, filepath.FromSlash(gitPath))
+		if relPath, err := filepath.Rel(workDir, absPath); err == nil {
+			return relPath
+		}
+		return absPath
+	}
+
+	diff, err := runGit(top, "diff", "--name-status", "-z", "--no-renames", commit, "--")
+	if err != nil {
+		return nil, err
+	}
+	changes := &gitChanges{}
+	fields := strings.Split(strings.TrimSuffix(diff, "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		status, gitPath := fields[i], fields[i+1]
+		if strings.HasPrefix(status, "D") {
+			changes.Removed = append(changes.Removed, localPath(gitPath))
+		} else {
+			changes.Changed = append(changes.Changed, localPath(gitPath))
+		}
+	}
+
+	untracked, err := runGit(top, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	for _, gitPath := range strings.Split(untracked, "\x00") {
+		if gitPath != "" {
+			changes.Changed = append(changes.Changed, localPath(gitPath))
+		}
+	}
+	return changes, nil
+}
+
+// incrementalPlan은 증분 색인에서 분석할 파일 목록입니다.
+type incrementalPlan struct {
+	Files     []string // 분석할 전체 파일 (바뀌지 않은 파일 포함, 경로 순서)
+	Unchanged []string // 소스를 다시 읽지 않고 이전 결과를 재사용할 파일
+	Changed   int      // 분석 대상 중 커밋 이후 추가되거나 수정된 파일 수
+	Removed   int      // 이전 매니페스트에서 삭제된 파일 수
+}
+
+// incrementalFiles는 이전 매니페스트의 파일에 커밋 이후 추가되거나 수정된 파일을 더하고, 삭제된 파일을 뺀 목록을 만듭니다.
+// 바뀌지 않은 파일도 목록에 남아야 프로젝트 단위 결과(심볼 색인, 호출 그래프 등)가 전체 파일을 담습니다.
+// 이 파일들은 Unchanged에 담아 소스를 읽지 않고 저장된 결과를 사용하게 합니다 (이전 실행에서 실패한 파일은 다시 분석).
+// 결과를 소스 옆에 저장하면 폴더를 탐색하지 않으므로, 삭제된 파일의 결과를 정리할 수 있도록 이전 결과 파일을 기록합니다.
+func (w *sourceWalker) incrementalFiles(since string) (*incrementalPlan, error) {
+	var previous project.Manifest
+	if err := project.ReadJSON(w.cfg.ProjectOutput, project.ManifestFileName, &previous); err != nil {
+		return nil, fmt.Errorf("no previous manifest to update, run a full index first: %w", err)
+	}
+	changes, err := gitChangesSince(since)
+	if err != nil {
+		return nil, err
+	}
+
+	absPaths := func(paths []string) map[string]bool {
+		set := make(map[string]bool, len(paths))
+		for _, path := range paths {
+			if absPath, err := filepath.Abs(path); err == nil {
+				set[absPath] = true
+			}
+		}
+		return set
+	}
+	removedPaths, changedPaths := absPaths(changes.Removed), absPaths(changes.Changed)
+
+	plan := &incrementalPlan{}
+	seen := make(map[string]bool)
+	add := func(path string) bool {
+		absPath, err := filepath.Abs(path)
+		if err != nil || seen[absPath] {
+			return false
+		}
+		seen[absPath] = true
+		if removedPaths[absPath] {
+			plan.Removed++
+			return false
+		}
+		plan.Files = append(plan.Files, path)
+		return !changedPaths[absPath]
+	}
+	for _, file := range previous.Files {
+		path := filepath.FromSlash(file.File)
+		if add(path) && file.Status != project.ManifestFailed {
+			plan.Unchanged = append(plan.Unchanged, path)
+		}
+		if w.outputRoot == "" && file.Output != "" {
+			w.outputs = append(w.outputs, filepath.FromSlash(file.Output))
+		}
+	}
+	for _, path := range changes.Changed {
+		add(path)
+		if w.indexes(path) {
+			plan.Changed++
+		}
+	}
+	sort.Strings(plan.Files)
+	return plan, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"SkelChunker/src/project"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newGitRepo는 임시 git 저장소를 만들어 현재 작업 폴더로 바꾸고, 테스트가 끝나면 되돌립니다.
+// 결과는 소스 파일 옆에 저장하도록 설정합니다.
+func newGitRepo(t *testing.T, files map[string]string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git이 설치되어 있지 않습니다")
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(workDir) })
+
+	files["config.json"] = `{"folders": ["src"], "output-root": "."}`
+	files[".gitignore"] = ".skelchunker/\n*.SkelChunker\n"
+	for name, content := range files {
+		writeFile(t, name, content)
+	}
+	git(t, "init", "-q")
+	git(t, "config", "user.email", "test@example.com")
+	git(t, "config", "user.name", "test")
+	git(t, "add", "-A")
+	git(t, "commit", "-q", "-m", "initial")
+}
+
+// git은 현재 작업 폴더에서 git 명령을 실행하고 표준 출력을 반환합니다.
+func git(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := runGit(".", args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(out)
+}
+
+func writeFile(t *testing.T, name string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readManifest(t *testing.T) map[string]project.ManifestFile {
+	t.Helper()
+	var manifest project.Manifest
+	if err := project.ReadJSON(".skelchunker", project.ManifestFileName, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]project.ManifestFile)
+	for _, file := range manifest.Files {
+		files[file.File] = file
+	}
+	return files
+}
+
+func TestIncrementalIndex(t *testing.T) {
+	newGitRepo(t, map[string]string{
+		"src/Keep.cs":   "class Keep { void Run() { new Added().Go(); } }\n",
+		"src/Modify.cs": "class Modify { void Old() { } }\n",
+		"src/Remove.cs": "class Remove { }\n",
+		"src/Rename.cs": "class Rename { void Move() { } }\n",
+	})
+	runIndex([]string{"-config", "config.json"})
+	before := readManifest(t)
+	if len(before) != 4 {
+		t.Fatalf("전체 색인 파일 수가 잘못되었습니다: %v", before)
+	}
+	commit := git(t, "rev-parse", "HEAD")
+
+	// 추가(추적하지 않는 새 파일), 수정, 삭제, 이름 변경
+	writeFile(t, "src/Added.cs", "class Added { public void Go() { } }\n")
+	writeFile(t, "src/Modify.cs", "class Modify { void New() { } }\n")
+	git(t, "rm", "-q", "src/Remove.cs")
+	git(t, "mv", "src/Rename.cs", "src/Renamed.cs")
+	// git이 바뀌지 않았다고 알려준 파일은 소스를 다시 읽지 않아야 함
+	git(t, "update-index", "--assume-unchanged", "src/Keep.cs")
+	writeFile(t, "src/Keep.cs", "class Keep { }\n")
+
+	runIndex([]string{"-config", "config.json", "-since", commit})
+	after := readManifest(t)
+
+	want := map[string]string{
+		"src/Added.cs":   project.ManifestAnalyzed,
+		"src/Keep.cs":    project.ManifestUnchanged,
+		"src/Modify.cs":  project.ManifestAnalyzed,
+		"src/Renamed.cs": project.ManifestAnalyzed,
+	}
+	if len(after) != len(want) {
+		t.Fatalf("증분 색인 파일 목록이 잘못되었습니다: %v", after)
+	}
+	for file, status := range want {
+		if after[file].Status != status {
+			t.Errorf("%s 상태가 잘못되었습니다: %q (기대값 %q)", file, after[file].Status, status)
+		}
+		if _, err := os.Stat(after[file].Output); err != nil {
+			t.Errorf("%s의 결과 파일이 없습니다: %v", file, err)
+		}
+	}
+	if after["src/Keep.cs"].MD5 != before["src/Keep.cs"].MD5 {
+		t.Errorf("바뀌지 않은 파일을 다시 읽었습니다")
+	}
+
+	// 삭제되거나 이름이 바뀐 파일의 결과는 소스 옆에 저장해도 정리되어야 함
+	for _, file := range []string{"src/Remove.cs", "src/Rename.cs"} {
+		if _, err := os.Stat(before[file].Output); !os.IsNotExist(err) {
+			t.Errorf("%s의 결과 파일이 정리되지 않았습니다: %v", file, err)
+		}
+	}
+
+	// 바뀌지 않은 파일의 식별자는 저장된 결과에서 읽어 새로 선언된 이름의 참조도 색인되어야 함
+	var symbols project.SymbolIndex
+	if err := project.ReadJSON(".skelchunker", project.SymbolsFileName, &symbols); err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]bool)
+	for _, symbol := range symbols.Symbols {
+		names[symbol.QualifiedName] = true
+	}
+	if !names["Modify.New"] || names["Modify.Old"] || !names["Rename.Move"] || names["Remove"] {
+		t.Errorf("심볼 색인이 변경을 반영하지 않았습니다: %v", names)
+	}
+	found := false
+	for _, ref := range symbols.References["Added"] {
+		if ref.File == "src/Keep.cs" && ref.Line == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("바뀌지 않은 파일의 참조가 색인되지 않았습니다: %v", symbols.References["Added"])
+	}
+}
@@ -0,0 +1,321 @@
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// pattern은 gitignore 문법의 패턴 한 줄입니다.
+type pattern struct {
+	text    string
+	negate  bool // !로 시작하여 앞선 패턴의 제외를 취소함
+	dirOnly bool // /로 끝나 폴더에만 적용됨
+	regex   *regexp.Regexp
+}
+
+// Rules는 한 폴더를 기준으로 하는 gitignore 문법의 패턴 목록입니다.
+type Rules struct {
+	base     string // 패턴의 기준 폴더 (절대 경로)
+	patterns []pattern
+}
+
+// NewRules는 기준 폴더와 패턴 줄들로 새로운 Rules를 생성합니다. 빈 줄과 #으로 시작하는 줄은 무시합니다.
+func NewRules(base string, lines []string) (*Rules, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve pattern folder: %w", err)
+	}
+	rules := &Rules{base: absBase}
+	for _, line := range lines {
+		p, ok, err := compilePattern(line)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			rules.patterns = append(rules.patterns, p)
+		}
+	}
+	return rules, nil
+}
+
+// ReadRules는 폴더의 무시 파일을 읽습니다. 파일이 없으면 nil을 반환하고, 해석할 수 없는 줄은 git처럼 건너뜁니다.
+func ReadRules(dir string, fileName string) (*Rules, error) {
+	file, err := os.Open(filepath.Join(dir, fileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Join(dir, fileName), err)
+	}
+	defer file.Close()
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve pattern folder: %w", err)
+	}
+	rules := &Rules{base: absDir}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if p, ok, err := compilePattern(scanner.Text()); err == nil && ok {
+			rules.patterns = append(rules.patterns, p)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Join(dir, fileName), err)
+	}
+	return rules, nil
+}
+
+// Match는 경로에 일치하는 마지막 패턴을 찾아, 일치하는 패턴이 있는지와 그 패턴이 제외(!가 아님)인지 반환합니다.
+// 기준 폴더 밖의 경로는 일치하지 않습니다.
+func (r *Rules) Match(absPath string, isDir bool) (matched bool, excluded bool) {
+	if r == nil {
+		return false, false
+	}
+	relPath, err := filepath.Rel(r.base, absPath)
+	if err != nil || relPath == "." || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return false, false
+	}
+	relPath = filepath.ToSlash(relPath)
+	for i := len(r.patterns) - 1; i >= 0; i-- {
+		p := r.patterns[i]
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.regex.MatchString(relPath) {
+			return true, !p.negate
+		}
+	}
+	return false, false
+}
+
+// compilePattern은 gitignore 문법의 한 줄을 정규식으로 바꿉니다. 패턴이 없는 줄이면 false입니다.
+//
+// 지원하는 문법:
+//   - #으로 시작하는 주석, !로 시작하는 부정 패턴 (\#, \!로 문자 그대로 사용)
+//   - /로 끝나는 패턴은 폴더에만 적용
+//   - 중간이나 앞에 /가 있으면 기준 폴더에 고정, 없으면 모든 단계의 이름에 일치
+//   - *, ?, [...] 와 **/, /**, /**/
+func compilePattern(line string) (pattern, bool, error) {
+	// 이스케이프하지 않은 끝 공백 제거
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern{}, false, nil
+	}
+
+	p := pattern{text: line}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return pattern{}, false, nil
+	}
+
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case strings.HasPrefix(line[i:], "**/") && (i == 0 || line[i-1] == '/'):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "**") && i+2 == len(line) && (i == 0 || line[i-1] == '/'):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(line[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+			class := line[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + strings.ReplaceAll(class, "\\", "\\\\") + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(line):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(line[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	regex, err := regexp.Compile(expr.String())
+	if err != nil {
+		return pattern{}, false, fmt.Errorf("invalid pattern %q: %w", p.text, err)
+	}
+	p.regex = regex
+	return p, true, nil
+}
+
+// Config는 Matcher를 만드는 설정입니다.
+type Config struct {
+	Root      string   // 무시 파일을 읽기 시작할 폴더 (보통 현재 작업 폴더, 분석 폴더가 그 밖이면 분석 폴더)
+	FileNames []string // 폴더마다 읽을 무시 파일 이름
+	Include   []string // 분석할 파일과 폴더 패턴 (분석 폴더 기준, 비어 있으면 모든 파일)
+	Exclude   []string // 제외할 파일과 폴더 패턴 (분석 폴더 기준)
+}
+
+// Matcher는 분석 폴더 하나에서 무시 파일과 설정의 포함/제외 패턴으로 분석할 경로를 판단합니다.
+// 무시 파일은 git과 같이 깊은 폴더의 패턴이 우선하며, 설정의 제외 패턴이 가장 우선합니다.
+// 여러 고루틴에서 동시에 사용할 수 있습니다.
+type Matcher struct {
+	folder    string // 분석 폴더 (절대 경로)
+	root      string
+	fileNames []string
+	include   *Rules
+	exclude   *Rules
+
+	mu       sync.Mutex
+	dirRules map[string][]*Rules // 폴더 → 그 폴더의 무시 파일 패턴
+}
+
+// NewMatcher는 분석 폴더의 새로운 Matcher를 생성합니다. 설정의 패턴이 잘못되었으면 오류를 반환합니다.
+func NewMatcher(folder string, config Config) (*Matcher, error) {
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve folder %s: %w", folder, err)
+	}
+	m := &Matcher{folder: absFolder, root: absFolder, fileNames: config.FileNames, dirRules: make(map[string][]*Rules)}
+	if config.Root != "" {
+		if root, err := filepath.Abs(config.Root); err == nil && isWithin(absFolder, root) {
+			m.root = root
+		}
+	}
+	if len(config.Include) > 0 {
+		if m.include, err = NewRules(absFolder, config.Include); err != nil {
+			return nil, fmt.Errorf("include patterns: %w", err)
+		}
+	}
+	if m.exclude, err = NewRules(absFolder, config.Exclude); err != nil {
+		return nil, fmt.Errorf("exclude patterns: %w", err)
+	}
+	return m, nil
+}
+
+// Ignored는 경로 또는 분석 폴더 아래의 상위 폴더가 무시 파일이나 제외 패턴에 의해 제외되는지 확인합니다.
+// 분석 폴더 자체는 제외하지 않습니다.
+func (m *Matcher) Ignored(path string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil || absPath == m.folder || !isWithin(absPath, m.folder) {
+		return false
+	}
+	for _, dir := range m.ancestors(absPath) {
+		if m.excluded(dir, true) {
+			return true
+		}
+	}
+	return m.excluded(absPath, isDir)
+}
+
+// Included는 파일이 포함 패턴에 일치하는지 확인합니다. 파일 또는 분석 폴더 아래의 상위 폴더가 일치하면 포함됩니다.
+// 포함 패턴이 없으면 모든 파일이 포함됩니다.
+func (m *Matcher) Included(path string) bool {
+	if m == nil || m.include == nil {
+		return true
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	if matched, included := m.include.Match(absPath, false); matched {
+		return included
+	}
+	for _, dir := range m.ancestors(absPath) {
+		if matched, included := m.include.Match(dir, true); matched && included {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded는 경로 한 단계에 대해 상위 폴더들의 무시 파일과 설정의 제외 패턴 중 마지막으로 일치하는 패턴을 적용합니다.
+func (m *Matcher) excluded(absPath string, isDir bool) bool {
+	// 무시 파일을 읽기 시작하는 폴더부터 경로의 상위 폴더까지, 위에서부터 차례로 적용
+	levels := []string{m.root}
+	if m.folder != m.root {
+		levels = append(levels, m.between(m.root, m.folder)...)
+		levels = append(levels, m.folder)
+	}
+	levels = append(levels, m.ancestors(absPath)...)
+
+	excluded := false
+	for _, level := range levels {
+		for _, rules := range m.rulesOf(level) {
+			if matched, ex := rules.Match(absPath, isDir); matched {
+				excluded = ex
+			}
+		}
+	}
+	if matched, ex := m.exclude.Match(absPath, isDir); matched {
+		excluded = ex
+	}
+	return excluded
+}
+
+// ancestors는 분석 폴더와 경로 사이의 폴더들을 위에서부터 반환합니다 (분석 폴더와 경로 자체는 제외).
+func (m *Matcher) ancestors(absPath string) []string {
+	return m.between(m.folder, absPath)
+}
+
+// between은 top 아래부터 bottom 위까지의 폴더들을 위에서부터 반환합니다.
+func (m *Matcher) between(top string, bottom string) []string {
+	var dirs []string
+	for dir := filepath.Dir(bottom); dir != top && isWithin(dir, top); dir = filepath.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+	}
+	return dirs
+}
+
+// rulesOf는 폴더의 무시 파일 패턴을 읽어 기억합니다.
+func (m *Matcher) rulesOf(dir string) []*Rules {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if rules, ok := m.dirRules[dir]; ok {
+		return rules
+	}
+	var rules []*Rules
+	for _, fileName := range m.fileNames {
+		if r, err := ReadRules(dir, fileName); err == nil && r != nil {
+			rules = append(rules, r)
+		}
+	}
+	m.dirRules[dir] = rules
+	return rules
+}
+
+// isWithin은 path가 dir 아래에 있는지 확인합니다 (같은 경로는 false).
+func isWithin(path string, dir string) bool {
+	relPath, err := filepath.Rel(dir, path)
+	return err == nil && relPath != "." && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator))
+}
@@ -4,12 +4,16 @@ import (
 	"SkelChunker/src/analyzer"
 	"SkelChunker/src/config"
 	"SkelChunker/src/embeddings"
+	"SkelChunker/src/model"
 	"SkelChunker/src/parser"
+	"SkelChunker/src/project"
 	"flag"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // shouldIgnoreFolder는 주어진 경로가 무시해야 할 폴더인지 확인합니다.
@@ -29,9 +33,59 @@ func shouldIgnoreFolder(path string, ignoreFolders []string) bool {
 }
 
 func main() {
+	// 첫 인자가 명령이 아니면(플래그이거나 없으면) index 명령으로 실행
+	command, args := "index", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "index":
+		runIndex(args)
+	case "def":
+		os.Exit(runDefinition(args))
+	case "refs":
+		os.Exit(runReferences(args))
+	case "hierarchy":
+		os.Exit(runHierarchy(args))
+	case "diagram":
+		os.Exit(runDiagram(args))
+	case "cache":
+		os.Exit(runCache(args))
+	case "clean":
+		os.Exit(runClean(args))
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", command)
+		printUsage()
+		os.Exit(2)
+	}
+}
+
+// printUsage는 명령 사용법을 출력합니다.
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  skelchunker [index] [-config config.json] [-since commit]")
+	fmt.Fprintln(os.Stderr, "                                                 analyze the configured folders (only files changed since a git commit)")
+	fmt.Fprintln(os.Stderr, "  skelchunker def [-config config.json] <symbol>  find where a symbol is declared")
+	fmt.Fprintln(os.Stderr, "  skelchunker refs [-config config.json] <symbol> find where a symbol is used")
+	fmt.Fprintln(os.Stderr, "  skelchunker hierarchy [-config config.json] [-json] <type>")
+	fmt.Fprintln(os.Stderr, "                                                 list ancestors, descendants and implementers of a type")
+	fmt.Fprintln(os.Stderr, "  skelchunker diagram [-config config.json] [-type classes|packages|calls] [-format dot|mermaid]")
+	fmt.Fprintln(os.Stderr, "                      [-folder dir] [-namespace ns] [-root symbol] [-depth n] [-by folder|namespace] [-o file]")
+	fmt.Fprintln(os.Stderr, "                                                 render a diagram from the analysis results")
+	fmt.Fprintln(os.Stderr, "  skelchunker clean [-config config.json] [-n]   remove outputs of deleted, renamed or ignored files")
+	fmt.Fprintln(os.Stderr, "  skelchunker cache stats [-config config.json]  show the embedding cache size")
+	fmt.Fprintln(os.Stderr, "  skelchunker cache prune [-config config.json] [-max-size-mb n] [-max-age-days n]")
+	fmt.Fprintln(os.Stderr, "                                                 evict least recently used embedding cache entries")
+}
+
+// runIndex는 설정된 폴더의 파일을 분석하고 파일별 결과와 프로젝트 단위 결과를 저장합니다.
+func runIndex(args []string) {
 	// 커맨드 라인 인자 파싱
-	configPath := flag.String("config", "config.json", "Path to configuration file")
-	flag.Parse()
+	flags := flag.NewFlagSet("index", flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Path to configuration file")
+	since := flags.String("since", "", "Analyze only files added or modified since this git commit")
+	flags.Parse(args)
 
 	// 설정 로드
 	cfg, err := config.LoadConfig(*configPath)
@@ -40,16 +94,24 @@ func main() {
 		os.Exit(1)
 	}
 
-	// 파서 팩토리 생성
-	parserFactory := parser.NewParserFactory()
+	// 색인한 커밋을 매니페스트에 기록 (git 저장소가 아니면 기록하지 않음)
+	commit, err := gitHead()
+	if err != nil && *since != "" {
+		fmt.Fprintf(os.Stderr, "Error reading git commit: %v\n", err)
+		os.Exit(1)
+	}
 
-	// 파서 등록
-	parserFactory.RegisterParser(parser.NewCSharpParser())
-	parserFactory.RegisterParser(parser.NewJavaScriptParser())
+	// 파서 팩토리 생성
+	parserFactory, err := newParserFactory(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in %v\n", err)
+		os.Exit(1)
+	}
 
 	// 임베딩 서비스 설정
 	var embeddingService embeddings.EmbeddingService
 	var embeddingConfig *embeddings.Config
+	var embeddingCache *embeddings.Cache
 	
 	if cfg.Embedding.Enabled {
 		embeddingConfig = &embeddings.Config{
@@ -57,6 +119,8 @@ func main() {
 			ModelName:  cfg.Embedding.ModelName,
 			VectorDim:  cfg.Embedding.VectorDim,
 			MaxTextSize: cfg.Embedding.MaxTextSize,
+			BatchSize:   cfg.Embedding.BatchSize,
+			BatchTokens: cfg.Embedding.BatchTokens,
 		}
 		
 		embeddingService = embeddings.NewOpenAIEmbedding(
@@ -64,6 +128,18 @@ func main() {
 			cfg.Embedding.ModelName,
 			cfg.Embedding.VectorDim,
 		)
+
+		// 디스크 임베딩 캐시를 서비스 앞에 둠 (여러 저장소와 실행이 함께 사용)
+		if cfg.Embedding.Cache.Enabled {
+			cache, err := embeddings.NewCache(cacheDir(cfg), cfg.Embedding.ModelName, cfg.Embedding.VectorDim, analyzer.PreprocessingVersion)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening embedding cache: %v\n", err)
+				os.Exit(1)
+			}
+			embeddingCache = cache
+			embeddingService = embeddings.NewCachedEmbedding(embeddingService, cache)
+			fmt.Println("Embedding cache:", cacheDir(cfg))
+		}
 		
 		fmt.Println("Embedding service initialized with model:", cfg.Embedding.ModelName)
 	} else {
@@ -71,49 +147,278 @@ func main() {
 	}
 
 	// 분석기 초기화
-	analyzer := analyzer.NewAnalyzer(parserFactory, embeddingService, embeddingConfig)
+	fileAnalyzer := analyzer.NewAnalyzer(parserFactory, embeddingService, embeddingConfig)
 
-	// 각 폴더 처리
-	for _, folder := range cfg.Folders {
-		err := filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
+	if err := configureOutput(fileAnalyzer, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in output configuration: %v\n", err)
+		os.Exit(1)
+	}
 
-			// 디렉토리인 경우 무시 폴더 체크
-			if info.IsDir() {
-				if shouldIgnoreFolder(path, cfg.IgnoreFolders) {
-					fmt.Printf("Skipping ignored folder: %s\n", path)
-					return filepath.SkipDir
-				}
-				return nil
-			}
+	// 원본이 없어진 결과를 기억하여 옮겨지거나 이름이 바뀐 파일이 임베딩을 이어받게 함
+	if err := fileAnalyzer.TrackRenames(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error tracking renamed files: %v\n", err)
+	}
 
-			// 파일 확장자 확인
-			ext := filepath.Ext(path)
-			if _, exists := cfg.Parsers[ext]; !exists {
-				return nil
-			}
+	// 폴더 탐색, 파싱, 임베딩을 단계별 작업자 풀로 동시에 수행
+	// 프로젝트 단위 분석을 위해 모든 파일의 결과를 모음 (작업자 수와 관계없이 탐색 순서로 정렬됨)
+	pipeline := analyzer.NewPipeline(fileAnalyzer, analyzer.PipelineConfig{
+		DiscoverWorkers: cfg.Workers.Discover,
+		ParseWorkers:    cfg.Workers.Parse,
+		EmbedWorkers:    cfg.Workers.Embed,
+	})
+	walker, err := newSourceWalker(cfg, parserFactory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in ignore configuration: %v\n", err)
+		os.Exit(1)
+	}
+	walk := walker.walk
+	if *since != "" {
+		// 폴더 전체를 탐색하지 않고 이전 매니페스트와 git 변경 목록으로 분석할 파일을 정함
+		plan, err := walker.incrementalFiles(*since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error in incremental index: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Incremental index since %s: %d files changed, %d removed\n", *since, plan.Changed, plan.Removed)
+		fileAnalyzer.ReuseResults(plan.Unchanged)
+		walk = walker.walkFiles(plan.Files)
+	}
+	results, errs := pipeline.Run(cfg.Folders, walk)
+	// 오류가 발생한 파일은 제외하고 계속 진행
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	if embeddingService != nil {
+		stats := fileAnalyzer.EmbeddingStats()
+		fmt.Printf("Embedded %d chunks, reused %d unchanged chunk embeddings\n", stats.Embedded, stats.Reused)
+	}
+	if embeddingCache != nil {
+		fmt.Printf("Embedding cache: %d hits, %d misses, %d stored\n", embeddingCache.Hits(), embeddingCache.Misses(), embeddingCache.Written())
 
-			// 파일 분석
-			result, err := analyzer.AnalyzeFile(path)
-			if err != nil {
-				fmt.Printf("Error analyzing file %s: %v\n", path, err)
-				return nil // 오류가 발생해도 계속 진행
-			}
+		// 크기와 기간 제한을 넘는 항목 정리
+		pruned, err := embeddings.PruneCache(cacheDir(cfg), int64(cfg.Embedding.Cache.MaxSizeMB)*1024*1024, time.Duration(cfg.Embedding.Cache.MaxAgeDays)*24*time.Hour)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error pruning embedding cache: %v\n", err)
+		} else if pruned.Entries > 0 {
+			fmt.Printf("Evicted %d embedding cache entries (%d bytes)\n", pruned.Entries, pruned.Bytes)
+		}
+	}
 
-			// 결과 저장
-			if err := analyzer.SaveResult(result); err != nil {
-				fmt.Printf("Error saving result for %s: %v\n", path, err)
-				return nil // 저장 오류가 발생해도 계속 진행
+	// 의존 구문을 프로젝트 파일로 연결 (모든 파일의 스켈레톤이 필요하므로 저장 전에 수행)
+	project.ResolveImports(results)
+
+	// 결과 저장
+	saveErrors := make(map[*model.AnalysisResult]error)
+	for _, result := range results {
+		if err := fileAnalyzer.SaveResult(result); err != nil {
+			fmt.Printf("Error saving result for %s: %v\n", project.FilePath(result), err)
+			saveErrors[result] = err
+			continue // 저장 오류가 발생해도 계속 진행
+		}
+		fmt.Printf("Successfully processed: %s\n", project.FilePath(result))
+	}
+
+	// 원본이 없어졌거나 더 이상 분석 대상이 아닌 결과 파일 정리
+	// (탐색에 실패한 폴더가 있으면 찾지 못한 파일의 결과를 지울 수 있으므로 건너뜀)
+	if len(pipeline.WalkErrors()) > 0 {
+		fmt.Fprintln(os.Stderr, "Skipping stale output cleanup because some folders could not be walked")
+	} else if stale, err := walker.staleOutputs(fileAnalyzer); err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding stale outputs: %v\n", err)
+	} else {
+		removed, errs := fileAnalyzer.RemoveOutputs(stale)
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		for _, item := range removed {
+			fmt.Printf("Removed stale output: %s\n", describeStaleOutput(item))
+		}
+	}
+
+	// 분석한 모든 파일의 상태를 요약한 매니페스트 (결과를 읽는 쪽이 파일을 찾아다니지 않도록)
+	manifest := buildManifest(cfg, fileAnalyzer, pipeline.Reports(), saveErrors)
+	manifest.Commit, manifest.Since = commit, *since
+	if err := project.WriteJSON(cfg.ProjectOutput, project.ManifestFileName, manifest); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving manifest: %v\n", err)
+	} else {
+		fmt.Printf("Wrote manifest of %d files (%d analyzed, %d unchanged, %d failed) to %s\n",
+			manifest.Totals.Files, manifest.Totals.Analyzed, manifest.Totals.Unchanged, manifest.Totals.Failed, cfg.ProjectOutput)
+	}
+
+	// 여러 파일에 나뉘어 선언된 partial 타입 병합
+	partials := project.MergePartialTypes(results)
+	if err := project.WriteJSON(cfg.ProjectOutput, project.PartialsFileName, partials); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving partial types: %v\n", err)
+	} else if len(partials) > 0 {
+		fmt.Printf("Merged %d partial types into %s\n", len(partials), cfg.ProjectOutput)
+	}
+
+	// 호출 위치를 프로젝트 안의 메서드와 함수로 연결한 호출 그래프
+	callGraph := project.BuildCallGraph(results)
+	if err := project.WriteJSON(cfg.ProjectOutput, project.CallGraphFileName, callGraph); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving call graph: %v\n", err)
+	} else {
+		fmt.Printf("Wrote call graph of %d members to %s\n", len(callGraph.Nodes), cfg.ProjectOutput)
+	}
+
+	// 파일 간 의존 관계 (diagram 명령의 패키지 다이어그램이 사용)
+	if err := project.WriteJSON(cfg.ProjectOutput, project.DependenciesFileName, project.BuildDependencyGraph(results)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving dependencies: %v\n", err)
+	}
+
+	// 상속/구현 관계 (hierarchy 명령이 사용)
+	hierarchy := project.BuildTypeHierarchy(results)
+	if err := project.WriteJSON(cfg.ProjectOutput, project.HierarchyFileName, hierarchy); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving type hierarchy: %v\n", err)
+	} else {
+		fmt.Printf("Wrote type hierarchy of %d types to %s\n", len(hierarchy.Types), cfg.ProjectOutput)
+	}
+
+	// def/refs 명령이 다시 파싱하지 않고 답할 수 있도록 심볼 색인 저장
+	symbols := project.BuildSymbolIndex(results)
+	if err := project.WriteJSON(cfg.ProjectOutput, project.SymbolsFileName, symbols); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving symbol index: %v\n", err)
+	} else {
+		fmt.Printf("Indexed %d symbols into %s\n", len(symbols.Symbols), cfg.ProjectOutput)
+	}
+}
+
+// buildManifest는 파이프라인의 파일별 결과와 저장 오류로 매니페스트를 만듭니다.
+func buildManifest(cfg *config.Config, fileAnalyzer *analyzer.Analyzer, reports []analyzer.FileReport, saveErrors map[*model.AnalysisResult]error) *project.Manifest {
+	embeddingModel := ""
+	if cfg.Embedding.Enabled {
+		embeddingModel = cfg.Embedding.ModelName
+	}
+
+	files := make([]project.ManifestFile, 0, len(reports))
+	for _, report := range reports {
+		file := project.ManifestFile{
+			File:     fileAnalyzer.RelativePath(report.Path),
+			Language: report.Language,
+			Parser:   report.Parser,
+			Status:   project.ManifestAnalyzed,
+		}
+		err := report.Err
+		if report.Result != nil {
+			file.File = path.Join(report.Result.Path, report.Result.Filename)
+			file.MD5 = report.Result.MD5
+			file.Chunks = len(report.Result.Chunks)
+			if len(report.Result.Embeddings) > 0 {
+				file.EmbeddingModel = embeddingModel
+			}
+			if report.Cached {
+				file.Status = project.ManifestUnchanged
 			}
+			if saveErr := saveErrors[report.Result]; saveErr != nil {
+				err = fmt.Errorf("failed to save result: %w", saveErr)
+			} else {
+				file.Output = filepath.ToSlash(fileAnalyzer.ResultPath(file.File))
+			}
+		}
+		if err != nil {
+			file.Status, file.Error = project.ManifestFailed, err.Error()
+		}
+		files = append(files, file)
+	}
+	return project.NewManifest(filepath.ToSlash(cfg.OutputRoot), embeddingModel, files)
+}
 
-			fmt.Printf("Successfully processed: %s\n", path)
-			return nil
-		})
+// configureOutput은 결과를 설정의 결과 폴더에 소스 트리 구조대로 저장하고,
+// 경로는 현재 작업 폴더 기준 상대 경로로 기록하도록 분석기를 설정합니다.
+func configureOutput(fileAnalyzer *analyzer.Analyzer, cfg *config.Config) error {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	return fileAnalyzer.ConfigureOutput(analyzer.OutputConfig{Root: cfg.OutputRoot, BaseDir: workDir})
+}
+
+// newParserFactory는 기본 파서, 플러그인, 규칙 기반 파서, tree-sitter 파서를 등록하고
+// 설정의 확장자 매핑과 판별 규칙을 적용한 파서 팩토리를 생성합니다.
+func newParserFactory(cfg *config.Config) (*parser.ParserFactory, error) {
+	parserFactory := parser.NewParserFactory()
+
+	// 파서 등록
+	parserFactory.RegisterParser(parser.NewCSharpParser())
+	parserFactory.RegisterParser(parser.NewJavaScriptParser())
+
+	// 외부 파서 플러그인 등록
+	for _, plugin := range cfg.Plugins {
+		if plugin.Name == "" || plugin.Command == "" {
+			return nil, fmt.Errorf("plugin configuration: name and command are required")
+		}
+		parserFactory.RegisterParser(parser.NewPluginParser(parser.PluginConfig{
+			Name:       plugin.Name,
+			Language:   plugin.Language,
+			Command:    plugin.Command,
+			Args:       plugin.Args,
+			Extensions: plugin.Extensions,
+			Timeout:    time.Duration(plugin.TimeoutSeconds) * time.Second,
+		}))
+	}
 
+	// 설정으로 정의한 규칙 기반 파서 등록
+	for _, ruleParser := range cfg.RuleParsers {
+		rules := make([]parser.RuleConfig, 0, len(ruleParser.Rules))
+		for _, rule := range ruleParser.Rules {
+			rules = append(rules, parser.RuleConfig{
+				Type:      rule.Type,
+				Kind:      rule.Kind,
+				Pattern:   rule.Pattern,
+				End:       rule.End,
+				Block:     rule.Block,
+				Container: rule.Container,
+			})
+		}
+		importRules := make([]parser.ImportRuleConfig, 0, len(ruleParser.Imports))
+		for _, rule := range ruleParser.Imports {
+			importRules = append(importRules, parser.ImportRuleConfig{Kind: rule.Kind, Pattern: rule.Pattern})
+		}
+		ruleBasedParser, err := parser.NewRuleParser(parser.RuleParserConfig{
+			Name:            ruleParser.Name,
+			Language:        ruleParser.Language,
+			Extensions:      ruleParser.Extensions,
+			Block:           ruleParser.Block,
+			Braces:          ruleParser.Braces,
+			CaseInsensitive: ruleParser.CaseInsensitive,
+			LineComments:    ruleParser.LineComments,
+			BlockComments:   ruleParser.BlockComments,
+			Strings:         ruleParser.Strings,
+			StringEscape:    ruleParser.StringEscape,
+			Rules:           rules,
+			Imports:         importRules,
+		})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error processing folder %s: %v\n", folder, err)
+			return nil, fmt.Errorf("rule parser configuration: %w", err)
 		}
+		parserFactory.RegisterParser(ruleBasedParser)
+	}
+
+	// tree-sitter 파서 등록 (-tags treesitter 로 빌드한 경우에만 treesitter_<언어> 파서가 추가됨)
+	if err := parser.RegisterTreeSitterParsers(parserFactory, cfg.TreeSitterQueries); err != nil {
+		return nil, fmt.Errorf("tree-sitter configuration: %w", err)
+	}
+
+	// 설정의 확장자별 파서 이름 적용 (존재하지 않는 파서 이름이면 시작하지 않음)
+	if err := parserFactory.Configure(cfg.Parsers); err != nil {
+		return nil, fmt.Errorf("parser configuration: %w", err)
 	}
+
+	// 확장자가 없거나 모호한 파일의 파서 판별 규칙 적용
+	detection := parser.DetectionConfig{
+		Filenames: cfg.Detection.Filenames,
+		Shebangs:  cfg.Detection.Shebangs,
+		Modelines: cfg.Detection.Modelines,
+	}
+	for _, rule := range cfg.Detection.Content {
+		detection.Content = append(detection.Content, parser.ContentRule{
+			Extensions: rule.Extensions,
+			Pattern:    rule.Pattern,
+			Parser:     rule.Parser,
+		})
+	}
+	if err := parserFactory.ConfigureDetection(detection); err != nil {
+		return nil, fmt.Errorf("detection configuration: %w", err)
+	}
+
+	return parserFactory, nil
 }
@@ -1,33 +1,145 @@
 package model
 
+// Range는 원본 파일 내의 위치를 나타내는 구조체입니다.
+// 라인은 1부터 시작하며, Start/End는 UTF-8 바이트 오프셋입니다. (End는 포함하지 않음)
+type Range struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+	Start     int `json:"start"`
+	End       int `json:"end"`
+}
+
+// Parameter는 메서드/함수의 매개변수를 나타내는 구조체입니다.
+type Parameter struct {
+	Name    string `json:"name"`
+	Type    string `json:"type,omitempty"`
+	Default string `json:"default,omitempty"`
+}
+
+// DocComment는 선언에 붙은 문서 주석(XML 문서 주석, JSDoc)을 나타내는 구조체입니다.
+type DocComment struct {
+	Summary string     `json:"summary,omitempty"`
+	Params  []ParamDoc `json:"params,omitempty"`
+	Returns string     `json:"returns,omitempty"`
+}
+
+// ParamDoc은 문서 주석의 매개변수 설명을 나타내는 구조체입니다.
+type ParamDoc struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// CallSite는 메서드/함수 본문 안의 호출 위치를 나타내는 구조체입니다.
+type CallSite struct {
+	Name     string `json:"name"`
+	Receiver string `json:"receiver,omitempty"` // obj.Name(...)의 obj (this, base 포함)
+	Kind     string `json:"kind,omitempty"`     // 생성자 호출(new Name(...))이면 new, 수신 객체가 식(a().Name(...))이면 member
+	Range    *Range `json:"range,omitempty"`
+}
+
 // Member는 클래스의 멤버(메서드)를 나타내는 구조체입니다.
 type Member struct {
-	MD5  string `json:"md5"`
-	Type string `json:"type"`
-	Name string `json:"name"`
+	MD5        string      `json:"md5"`
+	Type       string      `json:"type"`
+	Name       string      `json:"name"`
+	Range      *Range      `json:"range,omitempty"`
+	Signature  string      `json:"signature,omitempty"`
+	Parameters []Parameter `json:"parameters,omitempty"`
+	ReturnType string      `json:"returnType,omitempty"`
+	Visibility string      `json:"visibility,omitempty"`
+	Modifiers  []string    `json:"modifiers,omitempty"`
+	Doc        *DocComment `json:"doc,omitempty"`
+	Attributes []string    `json:"attributes,omitempty"`
+	Calls      []CallSite  `json:"calls,omitempty"`
+	File       string      `json:"file,omitempty"` // 여러 파일을 병합한 스켈레톤에서 멤버가 선언된 파일
+}
+
+// TypeParameter는 제네릭 타입 매개변수와 제약 조건을 나타내는 구조체입니다.
+type TypeParameter struct {
+	Name        string   `json:"name"`
+	Constraints []string `json:"constraints,omitempty"`
 }
 
 // SkeletonNode는 코드의 구조적 요소(클래스, 함수)를 나타내는 구조체입니다.
 type SkeletonNode struct {
-	Type    string   `json:"type"`
-	Name    string   `json:"name"`
-	Members []Member `json:"members,omitempty"`
-	MD5     string   `json:"md5,omitempty"`
+	Type       string      `json:"type"`
+	Name       string      `json:"name"`
+	Members    []Member    `json:"members,omitempty"`
+	MD5        string      `json:"md5,omitempty"`
+	Range      *Range      `json:"range,omitempty"`
+	Signature  string      `json:"signature,omitempty"`
+	Parameters []Parameter `json:"parameters,omitempty"`
+	ReturnType string      `json:"returnType,omitempty"`
+	Visibility string      `json:"visibility,omitempty"`
+	Modifiers  []string    `json:"modifiers,omitempty"`
+	Doc        *DocComment `json:"doc,omitempty"`
+	Attributes []string    `json:"attributes,omitempty"`
+	Calls      []CallSite  `json:"calls,omitempty"` // 함수 노드의 본문 안 호출
+
+	// 타입 선언(class)에만 해당하는 정보
+	Kind           string          `json:"kind,omitempty"` // class, interface, struct, record, enum
+	Namespace      string          `json:"namespace,omitempty"`
+	Parent         string          `json:"parent,omitempty"` // 중첩 타입을 감싸는 타입 이름
+	TypeParameters []TypeParameter `json:"typeParameters,omitempty"`
+	Extends        []string        `json:"extends,omitempty"`
+	Implements     []string        `json:"implements,omitempty"`
+}
+
+// QualifiedName은 네임스페이스와 감싸는 타입을 포함한 전체 이름을 반환합니다.
+func (n *SkeletonNode) QualifiedName() string {
+	name := n.Name
+	if n.Parent != "" {
+		name = n.Parent + "." + name
+	}
+	if n.Namespace != "" {
+		name = n.Namespace + "." + name
+	}
+	return name
 }
 
 // Chunk는 코드의 실제 구현 내용을 담는 구조체입니다.
 type Chunk struct {
 	MD5        string    `json:"md5"`
 	Text       string    `json:"text"`
+	Range      *Range    `json:"range,omitempty"`
 	Embeddings []float32 `json:"embeddings,omitempty"`
 }
 
+// Import는 파일이 의존하는 using/import/require/#include 구문입니다.
+type Import struct {
+	Kind      string   `json:"kind"`                // using, import, export, require, include
+	Path      string   `json:"path"`                // 네임스페이스, 모듈 경로 또는 파일 경로
+	Names     []string `json:"names,omitempty"`     // 가져온 이름 (import { a, b } from ...)
+	Alias     string   `json:"alias,omitempty"`     // 별칭 (using A = ..., import * as A from ...)
+	Modifiers []string `json:"modifiers,omitempty"` // global, static
+	Range     *Range   `json:"range,omitempty"`
+	Resolved  []string `json:"resolved,omitempty"` // 구문이 가리키는 프로젝트 안의 파일들
+}
+
+// Identifier는 소스에 나타난 식별자 하나의 위치입니다. 라인과 열은 1부터 시작하며, 열은 문자 단위입니다.
+type Identifier struct {
+	Name   string `json:"name,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+}
+
 // AnalysisResult는 파일 분석 결과를 나타내는 구조체입니다.
 type AnalysisResult struct {
-	Path       string         `json:"path"`
-	Filename   string         `json:"filename"`
-	MD5        string         `json:"md5"`
-	Embeddings [][]float32    `json:"embeddings,omitempty"`
-	Skeleton   []SkeletonNode `json:"skeleton"`
-	Chunks     []Chunk        `json:"chunks"`
-} 
\ No newline at end of file
+	Path           string         `json:"path"`
+	Filename       string         `json:"filename"`
+	MD5            string         `json:"md5"`
+	Version        int            `json:"version,omitempty"`        // 결과를 만든 분석 규칙의 버전 (다르면 결과를 재사용하지 않음)
+	Parser         string         `json:"parser,omitempty"`         // 결과를 만든 파서 (파서 매핑이 바뀌면 결과를 재사용하지 않음)
+	EmbeddingModel string         `json:"embeddingModel,omitempty"` // 임베딩을 만든 모델 (모델이 바뀌면 저장된 임베딩을 재사용하지 않음)
+	Embeddings     [][]float32    `json:"embeddings,omitempty"`
+	Imports        []Import       `json:"imports,omitempty"`
+	Skeleton       []SkeletonNode `json:"skeleton"`
+	Chunks         []Chunk        `json:"chunks"`
+
+	// Identifiers는 심볼 색인을 만들기 위한 식별자 위치입니다.
+	// 증분 색인이 바뀌지 않은 파일을 다시 읽지 않고 색인할 수 있도록 결과 파일에도 저장합니다.
+	Identifiers []Identifier `json:"identifiers"`
+}
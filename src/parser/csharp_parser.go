@@ -1,14 +1,16 @@
 package parser
 
 import (
-	"bufio"
 	"bytes"
 	"SkelChunker/src/model"
 	"strings"
 	"fmt"
+	"unicode"
+	"unicode/utf8"
 )
 
 // CSharpParser는 C# 소스 코드를 분석하는 파서입니다.
+// 필드는 한 번의 파싱 동안만 쓰는 상태이며, Parse는 호출마다 새 인스턴스에서 파싱합니다.
 type CSharpParser struct {
 	content []byte
 	pos     int
@@ -40,6 +42,7 @@ const (
 	TokenPunctuation
 	TokenComment
 	TokenWhitespace
+	TokenDirective
 )
 
 // 키워드 목록
@@ -48,6 +51,7 @@ var keywords = map[string]bool{
 	"interface":  true,
 	"struct":     true,
 	"record":     true,
+	"enum":       true,
 	"public":     true,
 	"private":    true,
 	"protected":  true,
@@ -92,7 +96,13 @@ func NewCSharpParser() *CSharpParser {
 }
 
 // Parse는 소스 코드를 분석하여 스켈레톤과 청크를 반환합니다.
+// 파싱 상태를 공유하지 않으므로 여러 고루틴에서 동시에 호출할 수 있습니다.
 func (p *CSharpParser) Parse(sourceCode string) ([]model.SkeletonNode, []model.Chunk, error) {
+	return (&CSharpParser{}).parse(sourceCode)
+}
+
+// parse는 이 인스턴스의 상태로 소스 코드를 분석합니다.
+func (p *CSharpParser) parse(sourceCode string) ([]model.SkeletonNode, []model.Chunk, error) {
 	p.content = []byte(sourceCode)
 	p.pos = 0
 	p.line = 1
@@ -118,8 +128,9 @@ func (p *CSharpParser) Parse(sourceCode string) ([]model.SkeletonNode, []model.C
 		// 파일 전체를 하나의 청크로 추가
 		chunks = []model.Chunk{
 			{
-				MD5:  fileMD5,
-				Text: sourceCode,
+				MD5:   fileMD5,
+				Text:  sourceCode,
+				Range: FileRange(sourceCode),
 			},
 		}
 	}
@@ -128,233 +139,183 @@ func (p *CSharpParser) Parse(sourceCode string) ([]model.SkeletonNode, []model.C
 }
 
 // tokenize는 소스 코드를 토큰으로 분리합니다.
+// 모든 오프셋은 UTF-8 바이트 단위이며, 컬럼은 라인 내 문자(rune) 단위입니다.
 func (p *CSharpParser) tokenize() error {
-	reader := bufio.NewReader(bytes.NewReader(p.content))
-	var buffer strings.Builder
-	inString := false
-	inChar := false
-	inLineComment := false
-	inBlockComment := false
-	
-	for {
-		ch, _, err := reader.ReadRune()
-		if err != nil {
-			break
-		}
+	// UTF-8 BOM은 토큰으로 만들지 않지만 오프셋은 원본 기준으로 유지
+	if bytes.HasPrefix(p.content, utf8BOM) {
+		p.pos = len(utf8BOM)
+	}
 
-		p.pos++
-		p.col++
+	for p.pos < len(p.content) {
+		start, line, col := p.pos, p.line, p.col
+		ch := p.peekRune(0)
+		next := p.peekRune(1)
 
-		if ch == '\n' {
-			p.line++
-			p.col = 1
-			
-			if inLineComment {
-				p.tokens = append(p.tokens, Token{
-					Type:  TokenComment,
-					Value: buffer.String(),
-					Line:  p.line - 1,
-					Col:   p.col,
-					Start: p.pos - buffer.Len() - 1,
-					End:   p.pos - 1,
-				})
-				buffer.Reset()
-				inLineComment = false
-			}
-		}
-
-		// 문자열 처리
-		if inString {
-			buffer.WriteRune(ch)
-			if ch == '"' && p.pos > 1 && p.content[p.pos-2] != '\\' {
-				p.tokens = append(p.tokens, Token{
-					Type:  TokenString,
-					Value: buffer.String(),
-					Line:  p.line,
-					Col:   p.col - buffer.Len(),
-					Start: p.pos - buffer.Len(),
-					End:   p.pos,
-				})
-				buffer.Reset()
-				inString = false
+		var tokenType TokenType
+		switch {
+		// 한 줄 주석 처리
+		case ch == '/' && next == '/':
+			for p.pos < len(p.content) && p.content[p.pos] != '\n' {
+				p.advance()
 			}
-			continue
-		}
+			tokenType = TokenComment
 
-		// 문자 처리
-		if inChar {
-			buffer.WriteRune(ch)
-			if ch == '\'' && p.pos > 1 && p.content[p.pos-2] != '\\' {
-				p.tokens = append(p.tokens, Token{
-					Type:  TokenString,
-					Value: buffer.String(),
-					Line:  p.line,
-					Col:   p.col - buffer.Len(),
-					Start: p.pos - buffer.Len(),
-					End:   p.pos,
-				})
-				buffer.Reset()
-				inChar = false
+		// 블록 주석 처리
+		case ch == '/' && next == '*':
+			p.advance()
+			p.advance()
+			for p.pos < len(p.content) && !(p.content[p.pos] == '*' && p.peekRune(1) == '/') {
+				p.advance()
 			}
-			continue
-		}
-
-		// 한 줄 주석 처리
-		if inLineComment {
-			buffer.WriteRune(ch)
-			continue
-		}
+			p.advance()
+			p.advance()
+			tokenType = TokenComment
+
+		// 전처리 지시문 처리 (#region, #if 등은 라인 전체를 하나의 토큰으로)
+		case ch == '#' && p.atLineStart():
+			for p.pos < len(p.content) && p.content[p.pos] != '\n' {
+				p.advance()
+			}
+			tokenType = TokenDirective
 
-		// 블록 주석 처리
-		if inBlockComment {
-			if ch == '/' && p.pos > 1 && p.content[p.pos-2] == '*' {
-				buffer.WriteRune(ch)
-				p.tokens = append(p.tokens, Token{
-					Type:  TokenComment,
-					Value: buffer.String(),
-					Line:  p.line,
-					Col:   p.col - buffer.Len(),
-					Start: p.pos - buffer.Len(),
-					End:   p.pos,
-				})
-				buffer.Reset()
-				inBlockComment = false
-			} else {
-				buffer.WriteRune(ch)
+		// 축자 문자열 처리 (@"...", $@"...", @$"...")
+		case ch == '@' && next == '"',
+			(ch == '$' && next == '@' || ch == '@' && next == '$') && p.peekRune(2) == '"':
+			for p.peekRune(0) != '"' {
+				p.advance()
 			}
-			continue
-		}
+			p.scanQuoted('"', true)
+			tokenType = TokenString
 
-		// 새로운 토큰 시작
-		switch {
-		case ch == '"':
-			inString = true
-			buffer.WriteRune(ch)
+		// 문자열 처리 ("...", $"...")
+		case ch == '"', ch == '$' && next == '"':
+			if ch == '$' {
+				p.advance()
+			}
+			p.scanQuoted('"', false)
+			tokenType = TokenString
 
+		// 문자 처리
 		case ch == '\'':
-			inChar = true
-			buffer.WriteRune(ch)
-
-		case ch == '/' && p.pos < len(p.content) && p.content[p.pos] == '/':
-			inLineComment = true
-			buffer.WriteString("//")
-			p.pos++
-			reader.ReadRune()
-			p.col++
-
-		case ch == '/' && p.pos < len(p.content) && p.content[p.pos] == '*':
-			inBlockComment = true
-			buffer.WriteString("/*")
-			p.pos++
-			reader.ReadRune()
-			p.col++
+			p.scanQuoted('\'', false)
+			tokenType = TokenString
 
 		case isLetter(ch) || ch == '_':
-			buffer.WriteRune(ch)
-			for {
-				nextCh, _, err := reader.ReadRune()
-				if err != nil || (!isLetter(nextCh) && !isDigit(nextCh) && nextCh != '_') {
-					if err == nil {
-						reader.UnreadRune()
-					}
+			for p.pos < len(p.content) {
+				c := p.peekRune(0)
+				if !isLetter(c) && !isDigit(c) && c != '_' {
 					break
 				}
-				buffer.WriteRune(nextCh)
-				p.pos++
-				p.col++
+				p.advance()
 			}
-
-			word := buffer.String()
-			tokenType := TokenIdentifier
-			if keywords[word] {
+			tokenType = TokenIdentifier
+			if keywords[string(p.content[start:p.pos])] {
 				tokenType = TokenKeyword
 			}
 
-			p.tokens = append(p.tokens, Token{
-				Type:  tokenType,
-				Value: word,
-				Line:  p.line,
-				Col:   p.col - buffer.Len(),
-				Start: p.pos - buffer.Len(),
-				End:   p.pos,
-			})
-			buffer.Reset()
-
 		case isDigit(ch):
-			buffer.WriteRune(ch)
-			for {
-				nextCh, _, err := reader.ReadRune()
-				if err != nil || (!isDigit(nextCh) && nextCh != '.') {
-					if err == nil {
-						reader.UnreadRune()
-					}
-					break
-				}
-				buffer.WriteRune(nextCh)
-				p.pos++
-				p.col++
-			}
-
-			p.tokens = append(p.tokens, Token{
-				Type:  TokenNumber,
-				Value: buffer.String(),
-				Line:  p.line,
-				Col:   p.col - buffer.Len(),
-				Start: p.pos - buffer.Len(),
-				End:   p.pos,
-			})
-			buffer.Reset()
+			for p.pos < len(p.content) && (isDigit(p.peekRune(0)) || p.peekRune(0) == '.') {
+				p.advance()
+			}
+			tokenType = TokenNumber
 
 		case isOperator(ch):
-			buffer.WriteRune(ch)
-			for {
-				nextCh, _, err := reader.ReadRune()
-				if err != nil || !isOperator(nextCh) {
-					if err == nil {
-						reader.UnreadRune()
-					}
-					break
-				}
-				buffer.WriteRune(nextCh)
-				p.pos++
-				p.col++
-			}
-
-			p.tokens = append(p.tokens, Token{
-				Type:  TokenOperator,
-				Value: buffer.String(),
-				Line:  p.line,
-				Col:   p.col - buffer.Len(),
-				Start: p.pos - buffer.Len(),
-				End:   p.pos,
-			})
-			buffer.Reset()
+			for p.pos < len(p.content) && isOperator(p.peekRune(0)) {
+				p.advance()
+			}
+			tokenType = TokenOperator
 
 		case isPunctuation(ch):
-			p.tokens = append(p.tokens, Token{
-				Type:  TokenPunctuation,
-				Value: string(ch),
-				Line:  p.line,
-				Col:   p.col,
-				Start: p.pos - 1,
-				End:   p.pos,
-			})
+			p.advance()
+			tokenType = TokenPunctuation
 
 		case isWhitespace(ch):
-			p.tokens = append(p.tokens, Token{
-				Type:  TokenWhitespace,
-				Value: string(ch),
-				Line:  p.line,
-				Col:   p.col,
-				Start: p.pos - 1,
-				End:   p.pos,
-			})
+			p.advance()
+			tokenType = TokenWhitespace
+
+		default:
+			// 인식하지 않는 문자(?, @ 등)는 토큰을 만들지 않음
+			p.advance()
+			continue
 		}
+
+		p.tokens = append(p.tokens, Token{
+			Type:  tokenType,
+			Value: string(p.content[start:p.pos]),
+			Line:  line,
+			Col:   col,
+			Start: start,
+			End:   p.pos,
+		})
 	}
 
 	return nil
 }
 
+// peekRune은 현재 위치에서 n번째 뒤의 문자를 반환합니다. 범위를 벗어나면 0을 반환합니다.
+func (p *CSharpParser) peekRune(n int) rune {
+	pos := p.pos
+	for ; n > 0 && pos < len(p.content); n-- {
+		_, size := utf8.DecodeRune(p.content[pos:])
+		pos += size
+	}
+	if pos >= len(p.content) {
+		return 0
+	}
+	ch, _ := utf8.DecodeRune(p.content[pos:])
+	return ch
+}
+
+// atLineStart는 현재 위치 앞에 같은 라인의 공백만 있는지 확인합니다.
+func (p *CSharpParser) atLineStart() bool {
+	for i := p.pos - 1; i >= 0 && p.content[i] != '\n'; i-- {
+		if p.content[i] != ' ' && p.content[i] != '\t' && p.content[i] != '\r' {
+			return i == len(utf8BOM)-1 && bytes.HasPrefix(p.content, utf8BOM)
+		}
+	}
+	return true
+}
+
+// advance는 한 문자만큼 위치를 이동하고 라인/컬럼 정보를 갱신합니다.
+func (p *CSharpParser) advance() {
+	if p.pos >= len(p.content) {
+		return
+	}
+	ch, size := utf8.DecodeRune(p.content[p.pos:])
+	p.pos += size
+	if ch == '\n' {
+		p.line++
+		p.col = 1
+	} else {
+		p.col++
+	}
+}
+
+// scanQuoted는 따옴표로 둘러싸인 리터럴을 끝까지 읽습니다.
+// verbatim이면 \ 이스케이프 대신 따옴표 두 번("")을 이스케이프로 취급합니다.
+func (p *CSharpParser) scanQuoted(quote rune, verbatim bool) {
+	p.advance() // 여는 따옴표
+	for p.pos < len(p.content) {
+		ch := p.peekRune(0)
+		switch {
+		case !verbatim && ch == '\\':
+			p.advance()
+			p.advance()
+		case ch == quote && verbatim && p.peekRune(1) == quote:
+			p.advance()
+			p.advance()
+		case ch == quote:
+			p.advance()
+			return
+		case ch == '\n' && !verbatim:
+			// 닫히지 않은 문자열은 라인 끝에서 종료
+			return
+		default:
+			p.advance()
+		}
+	}
+}
+
 // cleanupText는 텍스트에서 불필요한 문자나 흰색 공간을 정리합니다.
 func cleanupText(text string) string {
 	// 원본 텍스트를 그대로 반환
@@ -372,80 +333,162 @@ func (p *CSharpParser) parseTokens() ([]model.SkeletonNode, []model.Chunk, error
 	// 클래스/인터페이스/구조체/레코드가 있는지 확인
 	hasStructuredContent := false
 
+	// 현재 위치를 감싸는 네임스페이스와 타입 (end는 블록이 끝나는 토큰 위치)
+	var scopes []declarationScope
+
 	for i := 0; i < len(p.tokens); i++ {
 		token := p.tokens[i]
 
+		// 범위를 벗어난 네임스페이스/타입 제거
+		for len(scopes) > 0 && scopes[len(scopes)-1].end < i {
+			scopes = scopes[:len(scopes)-1]
+		}
+
+		// 네임스페이스 처리 (namespace A.B { } 또는 파일 범위 namespace A.B;)
+		if token.Type == TokenKeyword && token.Value == "namespace" {
+			nameStart := p.nextSignificant(i + 1)
+			if nameStart == -1 {
+				continue
+			}
+			bodyStart, namespaceEnd := p.findTypeBody(nameStart)
+			if namespaceEnd == -1 {
+				continue
+			}
+			nameEnd := namespaceEnd
+			if bodyStart != -1 {
+				nameEnd = bodyStart
+			} else {
+				// 파일 범위 네임스페이스는 파일 끝까지 적용
+				namespaceEnd = len(p.tokens)
+			}
+			scopes = append(scopes, declarationScope{
+				name: p.sourceText(nameStart, p.prevSignificant(nameEnd-1)),
+				end:  namespaceEnd,
+			})
+			i = nameEnd
+			continue
+		}
+
 		// 클래스/인터페이스/구조체/레코드 처리
-		if token.Type == TokenKeyword && (token.Value == "class" || token.Value == "interface" || token.Value == "struct" || token.Value == "record") {
+		if token.Type == TokenKeyword && isTypeKeyword(token.Value) {
+			// 제네릭 제약 조건(where T : class)이나 record라는 이름의 변수는 선언이 아님
+			if !p.isTypeDeclarationStart(i) {
+				continue
+			}
+
 			hasStructuredContent = true
 			// 클래스 이름 찾기
-			className := ""
 			classStart := i
-			classNamePos := i + 1
-			
-			for j := i + 1; j < len(p.tokens) && className == ""; j++ {
-				if p.tokens[j].Type == TokenIdentifier {
-					className = p.tokens[j].Value
-					classNamePos = j
-					break
-				}
-			}
-			
-			if className == "" {
+			classNamePos := p.nextSignificant(i + 1)
+			if classNamePos == -1 || p.tokens[classNamePos].Type != TokenIdentifier {
 				continue // 클래스 이름을 찾지 못함
 			}
+			className := p.tokens[classNamePos].Value
 
-			// 클래스 끝 위치 찾기
-			classEnd := p.findBlockEnd(classNamePos)
+			// 클래스 본문과 끝 위치 찾기 (본문 없는 record는 ;로 끝남)
+			bodyStart, classEnd := p.findTypeBody(classNamePos)
 			if classEnd == -1 {
 				continue
 			}
 
-			// 클래스 영역 추출
-			classStartOffset := p.tokens[classStart].Start
-			classEndOffset := p.tokens[classEnd].End
-			
-			if classStartOffset >= 0 && classEndOffset <= len(originalSource) && classStartOffset < classEndOffset {
-				classContent := originalSource[classStartOffset:classEndOffset]
-				classContent = cleanupText(classContent)
-
-				// 스켈레톤 노드 생성
-				classNode := &model.SkeletonNode{
-					Type:    "class",
-					Name:    className,
-					MD5:     "",
-					Members: []model.Member{},
-				}
+			// 클래스 제어자 추출
+			declStart := p.declarationStart(classStart)
+			visibility, modifiers := p.splitModifiers(declStart, classStart)
+			namespace, parent := scopeNames(scopes)
+			if visibility == "" && parent != "" {
+				visibility = "private"
+			} else if visibility == "" {
+				visibility = "internal"
+			}
+
+			// 클래스 앞에 붙은 문서 주석과 특성
+			attributes, doc, _ := p.leadingDecorations(declStart)
+
+			// 타입 종류 (record class, record struct는 record로 처리)
+			kind := token.Value
+			if prev := p.prevSignificant(classStart - 1); prev != -1 && p.tokens[prev].Value == "record" {
+				kind = "record"
+			}
+
+			// 제네릭 매개변수, 상속 목록, 제약 조건 추출
+			headerEnd := classEnd
+			if bodyStart != -1 {
+				headerEnd = bodyStart
+			}
+			typeParams, bases := p.parseTypeHeader(classNamePos, headerEnd)
+			extends, implements := classifyBaseTypes(kind, bases)
+
+			// 스켈레톤 노드 생성
+			classNode := &model.SkeletonNode{
+				Type:       "class",
+				Name:       className,
+				MD5:        "",
+				Members:    []model.Member{},
+				Range:      p.tokenRange(declStart, classEnd),
+				Visibility: visibility,
+				Modifiers:  modifiers,
+				Doc:        doc,
+				Attributes: attributes,
+				Kind:       kind,
+				Namespace:  namespace,
+				Parent:     parent,
+				Signature:  p.sourceText(declStart, p.prevSignificant(headerEnd-1)),
+
+				TypeParameters: typeParams,
+				Extends:        extends,
+				Implements:     implements,
+			}
 
-				// 메서드 찾기
-				methods := p.findMethodsInRange(classNamePos, classEnd)
-				for _, method := range methods {
-					// 메서드 코드 전체를 직접 추출한 내용 사용
-					methodContent := method.content
-					// 전체 메서드 코드를 정리
-					methodContent = cleanupText(methodContent)
-					// MD5 해시 계산
-					methodMD5 := calculateMD5(methodContent)
-
-					// 멤버 추가
-					classNode.Members = append(classNode.Members, model.Member{
-						Type: "method",
-						Name: method.name,
-						MD5:  methodMD5,
-					})
-
-					// 청크 추가 (변경된 메서드만 새로 생성)
-					chunks = append(chunks, model.Chunk{
-						MD5:  methodMD5,
-						Text: methodContent,
-					})
+			// 메서드 찾기 (열거형 값은 메서드가 아님)
+			var methods []methodInfo
+			if bodyStart != -1 && kind != "enum" {
+				defaultVisibility := "private"
+				if token.Value == "interface" {
+					defaultVisibility = "public"
 				}
+				methods = p.findMethodsInRange(bodyStart+1, classEnd, defaultVisibility)
+			}
+			for _, method := range methods {
+				// 메서드 코드 전체를 직접 추출한 내용 사용
+				methodContent := method.content
+				// 전체 메서드 코드를 정리
+				methodContent = cleanupText(methodContent)
+				// MD5 해시 계산
+				methodMD5 := calculateMD5(methodContent)
+
+				// 멤버 추가
+				classNode.Members = append(classNode.Members, model.Member{
+					Type:       "method",
+					Name:       method.name,
+					MD5:        methodMD5,
+					Range:      method.declRange,
+					Signature:  method.signature,
+					Parameters: method.parameters,
+					ReturnType: method.returnType,
+					Visibility: method.visibility,
+					Modifiers:  method.modifiers,
+					Doc:        method.doc,
+					Attributes: method.attributes,
+					Calls:      method.calls,
+				})
 
-				nodes = append(nodes, *classNode)
+				// 청크 추가 (변경된 메서드만 새로 생성)
+				chunks = append(chunks, model.Chunk{
+					MD5:   methodMD5,
+					Text:  methodContent,
+					Range: method.rng,
+				})
 			}
 
-			// 다음 토큰으로 건너뛰기
-			i = classEnd
+			nodes = append(nodes, *classNode)
+
+			// 중첩 클래스를 평면화하기 위해 본문 안쪽부터 계속 탐색
+			if bodyStart != -1 {
+				scopes = append(scopes, declarationScope{name: className, end: classEnd, isType: true})
+				i = bodyStart
+			} else {
+				i = classEnd
+			}
 		}
 	}
 
@@ -455,8 +498,9 @@ func (p *CSharpParser) parseTokens() ([]model.SkeletonNode, []model.Chunk, error
 		fileContent := cleanupText(originalSource)
 		fileMD5 := calculateMD5(fileContent)
 		chunks = append(chunks, model.Chunk{
-			MD5:  fileMD5,
-			Text: fileContent,
+			MD5:   fileMD5,
+			Text:  fileContent,
+			Range: FileRange(fileContent),
 		})
 	}
 
@@ -476,16 +520,6 @@ func extractMethodContent(source string, startLine, endLine int) string {
 		endLine = len(lines) - 1
 	}
 	
-	// 시작 라인 조정 (주석 포함)
-	for i := startLine - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if strings.HasPrefix(line, "//") || line == "" {
-			startLine = i
-		} else {
-			break
-		}
-	}
-	
 	var result strings.Builder
 	for i := startLine; i <= endLine; i++ {
 		result.WriteString(lines[i])
@@ -497,56 +531,812 @@ func extractMethodContent(source string, startLine, endLine int) string {
 	return result.String()
 }
 
-// findMethodsInRange는 주어진 범위 내에서 모든 메서드를 찾습니다.
-func (p *CSharpParser) findMethodsInRange(start, end int) []methodInfo {
+// findMethodsInRange는 클래스 본문(start~end) 안에서 모든 메서드를 찾습니다.
+// 메서드, 생성자, 속성, 인덱서, 연산자를 메서드로 처리하며 필드와 중첩 타입은 건너뜁니다.
+func (p *CSharpParser) findMethodsInRange(start, end int, defaultVisibility string) []methodInfo {
 	var methods []methodInfo
 	
 	// 원본 소스
 	originalSource := string(p.content)
+	offsets := lineOffsets(originalSource)
 	
-	for i := start; i < end; i++ {
-		if isMethodStart(p.tokens, i) {
-			methodName := getMethodName(p.tokens, i)
-			
-			// 메서드 시작 위치 찾기 - 라인 번호 기준
-			startLine := p.tokens[i].Line - 1  // 0-based 라인 번호로 변환
-			
-			// 메서드 본문 블록 찾기
-			methodBodyStart := i
-			for j := i; j < end; j++ {
-				if p.tokens[j].Type == TokenPunctuation && p.tokens[j].Value == "{" {
-					methodBodyStart = j
+	for i := p.nextSignificant(start); i != -1 && i < end; i = p.nextSignificant(i + 1) {
+		// 멤버 선언부의 끝 찾기 ({, =>, ; 또는 필드 초기화의 =)
+		headEnd := p.findMemberHeadEnd(i, end)
+		if headEnd == -1 {
+			break
+		}
+		terminator := p.tokens[headEnd].Value
+
+		// 특성([...])을 건너뛴 실제 선언 시작 위치
+		declStart := p.skipAttributes(i, headEnd)
+
+		// 멤버 끝 찾기
+		memberEnd := headEnd
+		switch terminator {
+		case "{":
+			memberEnd = p.findBlockEnd(headEnd)
+		case "=>", "=":
+			memberEnd = p.findStatementEnd(headEnd, end)
+		}
+		if memberEnd == -1 || memberEnd > end {
+			break
+		}
+		// 속성 초기화 ({ get; set; } = value;)
+		if terminator == "{" {
+			if next := p.nextSignificant(memberEnd + 1); next != -1 && next < end && p.tokens[next].Value == "=" {
+				memberEnd = p.findStatementEnd(next, end)
+				if memberEnd == -1 {
 					break
 				}
 			}
-			
-			// 메서드 끝 찾기
-			methodEnd := p.findBlockEnd(methodBodyStart)
-			if methodEnd == -1 || methodEnd > end {
-				continue
-			}
-			
-			// 메서드 끝 라인 번호
-			endLine := p.tokens[methodEnd].Line - 1  // 0-based 라인 번호
-			
+		}
+
+		method, ok := p.parseMemberHead(declStart, headEnd, memberEnd, defaultVisibility)
+		if ok {
+			// 앞에 붙은 문서 주석과 특성 찾기
+			leadStart := 0
+			method.attributes, method.doc, leadStart = p.leadingDecorations(declStart)
+
+			// 메서드 시작 위치 찾기 - 라인 번호 기준 (0-based, 앞쪽 주석과 특성 포함)
+			startLine := p.tokens[leadStart].Line - 1
+			endLine := p.tokens[memberEnd].Line - 1
+
 			// 메서드 내용 추출
 			methodContent := extractMethodContent(originalSource, startLine, endLine)
-			
-			methods = append(methods, methodInfo{
-				name:     methodName,
-				startPos: p.tokens[i].Start,
-				endPos:   p.tokens[methodEnd].End,
-				content:  methodContent,  // 직접 추출한 메서드 내용
-			})
-			
-			// 다음 토큰으로 건너뛰기
-			i = methodEnd
+
+			method.startPos = p.tokens[leadStart].Start
+			method.endPos = p.tokens[memberEnd].End
+			method.content = methodContent // 직접 추출한 메서드 내용
+			method.rng = lineRange(offsets, len(originalSource), startLine, endLine)
+			method.declRange = p.tokenRange(declStart, memberEnd)
+			method.calls = p.findCalls(headEnd, memberEnd)
+			methods = append(methods, method)
 		}
+		
+		// 다음 멤버로 건너뛰기
+		i = memberEnd
 	}
 	
 	return methods
 }
 
+// findMemberHeadEnd는 멤버 선언부가 끝나는 토큰({, =>, ;, =) 위치를 찾습니다.
+func (p *CSharpParser) findMemberHeadEnd(start, end int) int {
+	depth := 0
+	for i := start; i < end; i++ {
+		token := p.tokens[i]
+		switch {
+		case token.Type == TokenPunctuation && (token.Value == "(" || token.Value == "["):
+			depth++
+		case token.Type == TokenPunctuation && (token.Value == ")" || token.Value == "]"):
+			depth--
+		case depth > 0:
+			continue
+		case token.Type == TokenPunctuation && (token.Value == "{" || token.Value == ";"):
+			return i
+		case token.Type == TokenOperator && (token.Value == "=>" || token.Value == "="):
+			return i
+		}
+	}
+	return -1
+}
+
+// findStatementEnd는 중괄호 깊이 0에서 나오는 ; 위치를 찾습니다.
+func (p *CSharpParser) findStatementEnd(start, end int) int {
+	depth := 0
+	for i := start; i < end; i++ {
+		if p.tokens[i].Type != TokenPunctuation {
+			continue
+		}
+		switch p.tokens[i].Value {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		case ";":
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// findTypeBody는 타입 이름 뒤의 본문 시작({)과 끝(} 또는 ;) 위치를 찾습니다.
+// 본문 없이 ;로 끝나는 선언(record)은 본문 시작으로 -1을 반환합니다.
+func (p *CSharpParser) findTypeBody(namePos int) (int, int) {
+	depth := 0
+	for i := namePos; i < len(p.tokens); i++ {
+		token := p.tokens[i]
+		if token.Type != TokenPunctuation {
+			continue
+		}
+		switch token.Value {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		case "{":
+			if depth == 0 {
+				return i, p.findBlockEnd(i)
+			}
+		case ";":
+			if depth == 0 {
+				return -1, i
+			}
+		}
+	}
+	return -1, -1
+}
+
+// skipAttributes는 선언 앞에 붙은 특성([...])들을 건너뛴 위치를 반환합니다.
+func (p *CSharpParser) skipAttributes(start, end int) int {
+	i := start
+	for i < end && p.tokens[i].Value == "[" {
+		depth := 0
+		for ; i < end; i++ {
+			if p.tokens[i].Value == "[" {
+				depth++
+			} else if p.tokens[i].Value == "]" {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+		next := p.nextSignificant(i + 1)
+		if next == -1 || next > end {
+			return end
+		}
+		i = next
+	}
+	return i
+}
+
+// parseMemberHead는 멤버 선언부(start~headEnd)를 분석하여 이름과 시그니처 정보를 추출합니다.
+// 필드나 중첩 타입처럼 메서드로 처리하지 않는 선언이면 false를 반환합니다.
+func (p *CSharpParser) parseMemberHead(start, headEnd, memberEnd int, defaultVisibility string) (methodInfo, bool) {
+	var method methodInfo
+
+	// 제어자 건너뛰기 (public, private, static 등)
+	i := start
+	for i < headEnd && isModifier(p.tokens[i].Value) {
+		i = p.nextSignificant(i + 1)
+	}
+	method.visibility, method.modifiers = p.splitModifiers(start, i)
+	if method.visibility == "" {
+		method.visibility = defaultVisibility
+	}
+
+	// 매개변수 목록의 여는 괄호 찾기
+	nameEnd := -1
+	for j := i; j < headEnd; j++ {
+		token := p.tokens[j]
+		// 중첩 타입과 이벤트 필드는 메서드가 아님
+		if isTypeKeyword(token.Value) || token.Value == "event" {
+			return method, false
+		}
+		if token.Type != TokenPunctuation || token.Value != "(" {
+			continue
+		}
+		prev := p.prevSignificant(j - 1)
+		if prev >= i && (p.tokens[prev].Type == TokenIdentifier || p.tokens[prev].Value == ">" || p.tokens[prev].Value == ">>" ||
+			p.tokens[prev].Type == TokenOperator && prev > i && p.tokens[p.prevSignificant(prev-1)].Value == "operator") {
+			nameEnd = j
+			break
+		}
+		// 튜플 반환 타입 등은 괄호 끝으로 건너뜀
+		j = p.findMatching(j, headEnd)
+		if j == -1 {
+			return method, false
+		}
+	}
+
+	terminator := p.tokens[headEnd].Value
+	if nameEnd != -1 {
+		// 메서드, 생성자, 연산자
+		namePos := p.prevSignificant(nameEnd - 1)
+		// operator >, operator >> 는 제네릭 인자 목록의 끝이 아님
+		isOperator := namePos > i && p.tokens[p.prevSignificant(namePos-1)].Value == "operator"
+		if !isOperator && (p.tokens[namePos].Value == ">" || p.tokens[namePos].Value == ">>") {
+			namePos = p.prevSignificant(p.findGenericStart(namePos) - 1)
+		}
+		if namePos < i {
+			return method, false
+		}
+		typeEnd := namePos
+		method.name = p.tokens[namePos].Value
+		if prev := p.prevSignificant(namePos - 1); prev >= i {
+			switch p.tokens[prev].Value {
+			case "operator":
+				// 연산자 오버로드 (operator +)
+				method.name = "operator" + p.tokens[namePos].Value
+				typeEnd = prev
+				if p.tokens[namePos].Type == TokenIdentifier {
+					// 변환 연산자 (implicit operator int)
+					method.name = "operator " + p.tokens[namePos].Value
+					method.returnType = p.tokens[namePos].Value
+				}
+			case "~":
+				// 종료자 (~ClassName)
+				method.name = "~" + p.tokens[namePos].Value
+				typeEnd = prev
+			}
+		}
+		if typeEnd > i {
+			method.returnType = p.sourceText(i, p.prevSignificant(typeEnd-1))
+		}
+
+		closeParen := p.findMatching(nameEnd, headEnd)
+		if closeParen == -1 {
+			return method, false
+		}
+		method.parameters = p.parseParameters(nameEnd+1, closeParen)
+		method.signature = p.sourceText(start, closeParen)
+		return method, true
+	}
+
+	// 괄호가 없는 선언은 본문이 있는 경우에만 속성/인덱서로 처리
+	if terminator != "{" && terminator != "=>" {
+		return method, false
+	}
+	if terminator == "{" && !p.hasAccessor(headEnd, memberEnd) {
+		return method, false
+	}
+
+	namePos := p.prevSignificant(headEnd - 1)
+	if namePos < i {
+		return method, false
+	}
+	if p.tokens[namePos].Value == "]" {
+		// 인덱서 (this[int index])
+		open := namePos
+		for open > i && p.tokens[open].Value != "[" {
+			open--
+		}
+		method.parameters = p.parseParameters(open+1, namePos)
+		namePos = p.prevSignificant(open - 1)
+	}
+	method.name = p.tokens[namePos].Value
+	if namePos > i {
+		method.returnType = p.sourceText(i, p.prevSignificant(namePos-1))
+	}
+	method.signature = p.sourceText(start, p.prevSignificant(headEnd-1))
+	return method, true
+}
+
+// callPrefixWords는 호출 바로 앞에 올 수 있는 식별자 형태의 문맥 키워드입니다.
+// 그 밖의 식별자 뒤에 오는 Name(...)은 로컬 함수 선언(int Name(...))으로 봅니다.
+var callPrefixWords = map[string]bool{
+	"await": true, "yield": true, "in": true, "is": true, "as": true, "when": true,
+	"not": true, "and": true, "or": true, "select": true, "where": true, "let": true,
+}
+
+// nonCallWords는 괄호가 뒤따르지만 메서드 호출이 아닌 식별자 형태의 키워드입니다.
+var nonCallWords = map[string]bool{
+	"nameof": true, "typeof": true, "sizeof": true, "checked": true, "unchecked": true,
+	"lock": true, "fixed": true, "stackalloc": true, "when": true, "var": true,
+}
+
+// findCalls는 멤버 본문(start~end 토큰) 안의 메서드 호출과 생성자 호출을 찾습니다.
+func (p *CSharpParser) findCalls(start, end int) []model.CallSite {
+	var calls []model.CallSite
+	for i := start; i < end && i < len(p.tokens); i++ {
+		token := p.tokens[i]
+		if token.Type != TokenIdentifier || nonCallWords[token.Value] {
+			continue
+		}
+
+		// 이름 뒤에 (또는 제네릭 인자 <...>( 가 와야 호출
+		next := p.nextSignificant(i + 1)
+		if next != -1 && p.tokens[next].Value == "<" {
+			next = p.skipGenericArguments(next, end)
+		}
+		if next == -1 || next >= end || p.tokens[next].Value != "(" {
+			continue
+		}
+
+		call := model.CallSite{Name: token.Value, Range: p.tokenRange(i, i)}
+		prev := p.prevSignificant(i - 1)
+		switch {
+		case prev < start:
+		case p.tokens[prev].Value == "new":
+			call.Kind = "new"
+		case p.tokens[prev].Value == ".":
+			receiver := p.prevSignificant(prev - 1)
+			if receiver >= start && p.tokens[receiver].Type == TokenIdentifier {
+				call.Receiver = p.tokens[receiver].Value
+			} else {
+				call.Kind = "member"
+			}
+		case bytes.ContainsRune(p.content[p.tokens[prev].End:token.Start], '?'):
+			// 조건 연산자 (ok ? Name(...) : ...) - ?는 토큰으로 만들지 않음
+		case p.tokens[prev].Type == TokenIdentifier && !callPrefixWords[p.tokens[prev].Value],
+			p.tokens[prev].Value == ">" || p.tokens[prev].Value == "]":
+			// 로컬 함수 선언 (int Local(...), List<int> Local(...))
+			continue
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// skipGenericArguments는 제네릭 인자 목록(<...>) 다음의 의미 있는 토큰 위치를 반환합니다.
+// 꺾쇠 안에 타입 이름이 아닌 토큰이 있으면 비교 연산자로 보고 -1을 반환합니다.
+func (p *CSharpParser) skipGenericArguments(open, end int) int {
+	depth := 0
+	for i := open; i < end; i++ {
+		token := p.tokens[i]
+		if isTrivia(token) {
+			continue
+		}
+		switch {
+		case token.Type == TokenOperator && strings.Trim(token.Value, "<>") == "":
+			depth += strings.Count(token.Value, "<") - strings.Count(token.Value, ">")
+			if depth <= 0 {
+				return p.nextSignificant(i + 1)
+			}
+		case token.Type == TokenIdentifier, token.Type == TokenKeyword,
+			token.Value == ",", token.Value == ".", token.Value == "[", token.Value == "]":
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+// leadingDecorations는 선언(pos) 바로 앞에 붙은 특성과 문서 주석을 찾습니다.
+// 특성 목록, 문서 주석, 그리고 청크에 포함할 첫 토큰 위치를 반환합니다.
+// 다른 코드와 같은 라인에 있는 주석(} // 끝)은 앞 선언의 것이므로 포함하지 않습니다.
+func (p *CSharpParser) leadingDecorations(pos int) ([]string, *model.DocComment, int) {
+	var attributes []string
+	var docComments []string
+	start := pos
+
+scan:
+	for i := pos - 1; i >= 0; i-- {
+		token := p.tokens[i]
+		switch {
+		case token.Type == TokenWhitespace:
+			continue
+		case token.Type == TokenComment && p.startsLine(i):
+			if isDocComment(token.Value) {
+				docComments = append([]string{token.Value}, docComments...)
+			}
+			start = i
+		case token.Type == TokenPunctuation && token.Value == "]":
+			open := p.findMatchingBackward(i)
+			if open == -1 {
+				break scan
+			}
+			attributes = append(p.splitAttributes(open, i), attributes...)
+			start = open
+			i = open
+		default:
+			break scan
+		}
+	}
+
+	var doc *model.DocComment
+	if len(docComments) > 0 {
+		doc = parseDocComment(docComments)
+	}
+	return attributes, doc, start
+}
+
+// splitAttributes는 특성 블록([A, B(1)])을 개별 특성 텍스트로 분리합니다.
+func (p *CSharpParser) splitAttributes(open, close int) []string {
+	var attributes []string
+	depth := 0
+	itemStart := open + 1
+	for i := open + 1; i <= close; i++ {
+		switch p.tokens[i].Value {
+		case "(", "[", "{":
+			depth++
+		case ")", "}":
+			depth--
+		case "]":
+			if i != close {
+				depth--
+				continue
+			}
+			fallthrough
+		case ",":
+			if depth > 0 {
+				continue
+			}
+			first := p.nextSignificant(itemStart)
+			last := p.prevSignificant(i - 1)
+			if first != -1 && first <= last {
+				attributes = append(attributes, p.sourceText(first, last))
+			}
+			itemStart = i + 1
+		}
+	}
+	return attributes
+}
+
+// findMatchingBackward는 닫는 대괄호(])에 대응하는 여는 대괄호([) 위치를 거슬러 올라가며 찾습니다.
+func (p *CSharpParser) findMatchingBackward(close int) int {
+	depth := 0
+	for i := close; i >= 0; i-- {
+		switch p.tokens[i].Value {
+		case "]":
+			depth++
+		case "[":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// startsLine은 토큰 앞에 같은 라인의 공백만 있는지 확인합니다.
+func (p *CSharpParser) startsLine(pos int) bool {
+	for i := pos - 1; i >= 0; i-- {
+		token := p.tokens[i]
+		if token.Type != TokenWhitespace {
+			return false
+		}
+		if token.Value == "\n" {
+			return true
+		}
+	}
+	return true
+}
+
+// parseTypeHeader는 타입 이름(namePos) 뒤부터 본문 시작(end) 전까지의 선언부를 분석하여
+// 제네릭 매개변수(제약 조건 포함)와 상속 목록을 반환합니다.
+func (p *CSharpParser) parseTypeHeader(namePos, end int) ([]model.TypeParameter, []string) {
+	var typeParams []model.TypeParameter
+	var bases []string
+
+	i := p.nextSignificant(namePos + 1)
+
+	// 제네릭 매개변수 (<in T, out U>)
+	if i != -1 && i < end && p.tokens[i].Value == "<" {
+		close := i
+		for depth := 0; close < end; close++ {
+			depth += strings.Count(p.tokens[close].Value, "<") - strings.Count(p.tokens[close].Value, ">")
+			if p.tokens[close].Type == TokenOperator && depth <= 0 {
+				break
+			}
+		}
+		for _, name := range p.splitTopLevel(i+1, close) {
+			fields := strings.Fields(name)
+			typeParams = append(typeParams, model.TypeParameter{Name: fields[len(fields)-1]})
+		}
+		i = p.nextSignificant(close + 1)
+	}
+
+	// record의 주 생성자 매개변수 건너뛰기
+	if i != -1 && i < end && p.tokens[i].Value == "(" {
+		i = p.nextSignificant(p.findMatching(i, end) + 1)
+	}
+
+	// 상속 목록 (: Base, IInterface)
+	if i != -1 && i < end && p.tokens[i].Value == ":" {
+		listEnd := p.findWhereClause(i, end)
+		for _, base := range p.splitTopLevel(i+1, listEnd) {
+			// record의 기본 생성자 인자 제거 (: Base(Name))
+			if paren := strings.Index(base, "("); paren > 0 {
+				base = strings.TrimSpace(base[:paren])
+			}
+			bases = append(bases, base)
+		}
+		i = listEnd
+	}
+
+	// 제약 조건 (where T : class, new())
+	for i != -1 && i < end && p.tokens[i].Value == "where" {
+		namePos := p.nextSignificant(i + 1)
+		colon := p.nextSignificant(namePos + 1)
+		if namePos == -1 || colon == -1 || colon >= end || p.tokens[colon].Value != ":" {
+			break
+		}
+		next := p.findWhereClause(colon, end)
+		constraints := p.splitTopLevel(colon+1, next)
+		for j := range typeParams {
+			if typeParams[j].Name == p.tokens[namePos].Value {
+				typeParams[j].Constraints = constraints
+			}
+		}
+		i = next
+	}
+
+	return typeParams, bases
+}
+
+// findWhereClause는 start 이후 다음 where 절의 시작 위치를 찾습니다. 없으면 end를 반환합니다.
+func (p *CSharpParser) findWhereClause(start, end int) int {
+	depth := 0
+	for i := start + 1; i < end; i++ {
+		switch p.tokens[i].Value {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		case "where":
+			if depth == 0 && p.tokens[i].Type == TokenIdentifier {
+				return i
+			}
+		}
+	}
+	return end
+}
+
+// splitTopLevel은 start~end(미포함) 사이의 토큰을 괄호/꺾쇠 밖의 쉼표로 나눈 텍스트 목록을 반환합니다.
+func (p *CSharpParser) splitTopLevel(start, end int) []string {
+	var items []string
+	depth := 0
+	itemStart := start
+	for i := start; i <= end; i++ {
+		if i < end {
+			value := p.tokens[i].Value
+			switch {
+			case value == "(" || value == "[" || value == "{":
+				depth++
+				continue
+			case value == ")" || value == "]" || value == "}":
+				depth--
+				continue
+			case p.tokens[i].Type == TokenOperator:
+				depth += strings.Count(value, "<") - strings.Count(value, ">")
+				continue
+			case value != "," || depth > 0:
+				continue
+			}
+		}
+		first := p.nextSignificant(itemStart)
+		last := p.prevSignificant(i - 1)
+		if first != -1 && first <= last && last < end {
+			items = append(items, p.sourceText(first, last))
+		}
+		itemStart = i + 1
+	}
+	return items
+}
+
+// classifyBaseTypes는 상속 목록을 기본 타입(extends)과 구현 인터페이스(implements)로 나눕니다.
+// C#은 구문만으로 둘을 구분할 수 없으므로 클래스/레코드의 첫 항목이 I로 시작하는
+// 인터페이스 이름 규칙(IName)을 따르지 않으면 기본 클래스로 봅니다.
+func classifyBaseTypes(kind string, bases []string) ([]string, []string) {
+	switch {
+	case len(bases) == 0 || kind == "enum":
+		return nil, nil
+	case kind == "interface":
+		return bases, nil
+	case kind == "struct":
+		return nil, bases
+	case looksLikeInterface(bases[0]):
+		return nil, bases
+	default:
+		return bases[:1], bases[1:]
+	}
+}
+
+// looksLikeInterface는 타입 이름이 C# 인터페이스 명명 규칙(IName)을 따르는지 확인합니다.
+func looksLikeInterface(name string) bool {
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	runes := []rune(name)
+	return len(runes) >= 2 && runes[0] == 'I' && unicode.IsUpper(runes[1])
+}
+
+// declarationScope는 선언을 감싸는 네임스페이스 또는 타입의 범위를 나타냅니다.
+type declarationScope struct {
+	name   string
+	end    int
+	isType bool
+}
+
+// scopeNames는 범위 목록에서 네임스페이스와 감싸는 타입의 이름(점으로 연결)을 만듭니다.
+func scopeNames(scopes []declarationScope) (string, string) {
+	var namespaces, types []string
+	for _, scope := range scopes {
+		if scope.isType {
+			types = append(types, scope.name)
+		} else {
+			namespaces = append(namespaces, scope.name)
+		}
+	}
+	return strings.Join(namespaces, "."), strings.Join(types, ".")
+}
+
+// isTypeKeyword는 타입 선언을 시작하는 키워드인지 확인합니다.
+func isTypeKeyword(word string) bool {
+	return word == "class" || word == "interface" || word == "struct" || word == "record" || word == "enum"
+}
+
+// isTypeDeclarationStart는 pos의 타입 키워드가 실제 타입 선언을 시작하는지 확인합니다.
+// 바로 앞 토큰이 제어자, 블록/문장 경계, 특성이어야 선언으로 봅니다.
+func (p *CSharpParser) isTypeDeclarationStart(pos int) bool {
+	prev := p.prevSignificant(pos - 1)
+	if prev == -1 {
+		return true
+	}
+	switch p.tokens[prev].Value {
+	case "{", "}", ";", "]", "record":
+		return true
+	}
+	return isModifier(p.tokens[prev].Value)
+}
+
+// hasAccessor는 블록 안에 get/set/init/add/remove 접근자가 있는지 확인합니다.
+func (p *CSharpParser) hasAccessor(blockStart, blockEnd int) bool {
+	for i := blockStart + 1; i < blockEnd; i++ {
+		switch p.tokens[i].Value {
+		case "get", "set", "init", "add", "remove":
+			return true
+		}
+	}
+	return false
+}
+
+// parseParameters는 괄호 안의 토큰(start~end, end 미포함)을 매개변수 목록으로 변환합니다.
+func (p *CSharpParser) parseParameters(start, end int) []model.Parameter {
+	var params []model.Parameter
+
+	paramStart := start
+	depth := 0
+	for i := start; i <= end; i++ {
+		if i < end {
+			switch p.tokens[i].Value {
+			case "(", "[", "{", "<":
+				depth++
+				continue
+			case ")", "]", "}", ">":
+				depth--
+				continue
+			case ">>":
+				depth -= 2
+				continue
+			case ",":
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		if param, ok := p.parseParameter(paramStart, i); ok {
+			params = append(params, param)
+		}
+		paramStart = i + 1
+	}
+
+	return params
+}
+
+// parseParameter는 하나의 매개변수 선언(start~end, end 미포함)을 분석합니다.
+func (p *CSharpParser) parseParameter(start, end int) (model.Parameter, bool) {
+	var param model.Parameter
+
+	first := p.nextSignificant(start)
+	if first == -1 || first >= end {
+		return param, false
+	}
+	first = p.skipAttributes(first, end)
+
+	// 기본값 분리
+	last := end
+	for i := first; i < end; i++ {
+		if p.tokens[i].Type == TokenOperator && p.tokens[i].Value == "=" {
+			if valueStart := p.nextSignificant(i + 1); valueStart != -1 && valueStart < end {
+				param.Default = p.sourceText(valueStart, p.prevSignificant(end-1))
+			}
+			last = i
+			break
+		}
+	}
+
+	namePos := p.prevSignificant(last - 1)
+	if namePos < first {
+		return param, false
+	}
+	param.Name = p.tokens[namePos].Value
+	if namePos > first {
+		param.Type = p.sourceText(first, p.prevSignificant(namePos-1))
+	}
+	return param, true
+}
+
+// splitModifiers는 start~end 사이의 제어자를 접근 제한자와 나머지 제어자로 분리합니다.
+func (p *CSharpParser) splitModifiers(start, end int) (string, []string) {
+	var visibility []string
+	var modifiers []string
+	for i := start; i < end && i != -1; i = p.nextSignificant(i + 1) {
+		word := p.tokens[i].Value
+		switch word {
+		case "public", "private", "protected", "internal":
+			visibility = append(visibility, word)
+		default:
+			if isModifier(word) {
+				modifiers = append(modifiers, word)
+			}
+		}
+	}
+	return strings.Join(visibility, " "), modifiers
+}
+
+// findMatching은 여는 괄호에 대응하는 닫는 괄호 위치를 찾습니다.
+func (p *CSharpParser) findMatching(open, end int) int {
+	closing := map[string]string{"(": ")", "[": "]", "{": "}"}[p.tokens[open].Value]
+	depth := 0
+	for i := open; i < end; i++ {
+		switch p.tokens[i].Value {
+		case p.tokens[open].Value:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// findGenericStart는 제네릭 인자의 닫는 꺾쇠(>)에 대응하는 여는 꺾쇠(<) 위치를 찾습니다.
+func (p *CSharpParser) findGenericStart(close int) int {
+	depth := 0
+	for i := close; i >= 0; i-- {
+		if p.tokens[i].Type != TokenOperator {
+			continue
+		}
+		depth += strings.Count(p.tokens[i].Value, ">") - strings.Count(p.tokens[i].Value, "<")
+		if depth <= 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// sourceText는 두 토큰 사이(양 끝 포함)의 원본 텍스트를 공백을 정리하여 반환합니다.
+func (p *CSharpParser) sourceText(start, end int) string {
+	if start < 0 || end < start {
+		return ""
+	}
+	var parts []string
+	for i := start; i <= end; i++ {
+		if p.tokens[i].Type == TokenComment || p.tokens[i].Type == TokenDirective {
+			continue
+		}
+		parts = append(parts, p.tokens[i].Value)
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, "")), " ")
+}
+
+// nextSignificant는 pos부터 처음 나오는 의미 있는 토큰(공백, 주석 제외) 위치를 반환합니다. 없으면 -1.
+func (p *CSharpParser) nextSignificant(pos int) int {
+	for i := pos; i < len(p.tokens); i++ {
+		if !isTrivia(p.tokens[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// prevSignificant는 pos부터 거슬러 올라가며 처음 나오는 의미 있는 토큰 위치를 반환합니다. 없으면 -1.
+func (p *CSharpParser) prevSignificant(pos int) int {
+	for i := pos; i >= 0 && i < len(p.tokens); i-- {
+		if !isTrivia(p.tokens[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isTrivia는 구문 분석에서 무시하는 토큰(공백, 주석, 전처리 지시문)인지 확인합니다.
+func isTrivia(token Token) bool {
+	return token.Type == TokenWhitespace || token.Type == TokenComment || token.Type == TokenDirective
+}
+
 // findBlockEnd는 중괄호 블록의 끝을 찾습니다.
 func (p *CSharpParser) findBlockEnd(start int) int {
 	braceLevel := 0
@@ -569,6 +1359,41 @@ func (p *CSharpParser) findBlockEnd(start int) int {
 	return -1
 }
 
+// tokenRange는 두 토큰 사이(양 끝 포함)의 원본 범위를 반환합니다.
+func (p *CSharpParser) tokenRange(start, end int) *model.Range {
+	return &model.Range{
+		StartLine: p.tokens[start].Line,
+		EndLine:   p.tokens[end].Line,
+		Start:     p.tokens[start].Start,
+		End:       p.tokens[end].End,
+	}
+}
+
+// declarationStart는 이름 앞에 오는 제어자와 타입을 포함한 선언의 첫 토큰 위치를 찾습니다.
+// 같은 라인 안에서만 거슬러 올라가므로 이전 라인의 주석이나 특성은 포함하지 않습니다.
+func (p *CSharpParser) declarationStart(pos int) int {
+	start := pos
+	for i := pos - 1; i >= 0; i-- {
+		token := p.tokens[i]
+		switch {
+		case token.Type == TokenWhitespace && token.Value != "\n":
+			continue
+		case token.Type == TokenKeyword || token.Type == TokenIdentifier:
+			start = i
+		case token.Type == TokenOperator && strings.Trim(token.Value, "<>") == "":
+			start = i
+		case token.Type == TokenPunctuation && (token.Value == "." || token.Value == ","):
+			start = i
+		case token.Type == TokenPunctuation && token.Value == "]" && i > 0 && p.tokens[i-1].Value == "[":
+			start = i - 1
+			i--
+		default:
+			return start
+		}
+	}
+	return start
+}
+
 // extractTextBetween은 두 위치 사이의 원본 텍스트를 추출합니다.
 func (p *CSharpParser) extractTextBetween(start, end int) string {
 	if start >= len(p.tokens) || end >= len(p.tokens) || start < 0 || end < 0 {
@@ -593,86 +1418,6 @@ func (p *CSharpParser) extractTextBetween(start, end int) string {
 	return string(p.content[startPos:endPos])
 }
 
-// isMethodStart는 현재 위치가 메서드의 시작인지 확인합니다.
-func isMethodStart(tokens []Token, pos int) bool {
-	if pos+2 >= len(tokens) {
-		return false
-	}
-
-	// 제어자 건너뛰기 (public, private, static 등)
-	i := pos
-	for i < len(tokens) && tokens[i].Type == TokenKeyword && isModifier(tokens[i].Value) {
-		i++
-	}
-	
-	if i+2 >= len(tokens) {
-		return false
-	}
-
-	// 반환 타입 + 이름 + ( 패턴
-	if ((tokens[i].Type == TokenIdentifier || tokens[i].Type == TokenKeyword) && 
-		tokens[i+1].Type == TokenIdentifier && 
-		i+2 < len(tokens) && tokens[i+2].Type == TokenPunctuation && tokens[i+2].Value == "(") {
-		return true
-	}
-
-	// 속성 패턴 (Type Name { get; set; })
-	if ((tokens[i].Type == TokenIdentifier || tokens[i].Type == TokenKeyword) && 
-		tokens[i+1].Type == TokenIdentifier && 
-		i+2 < len(tokens) && tokens[i+2].Type == TokenPunctuation && tokens[i+2].Value == "{") {
-		for j := i+3; j < len(tokens) && j < i+10; j++ {
-			if tokens[j].Type == TokenKeyword && (tokens[j].Value == "get" || tokens[j].Value == "set") {
-				return true
-			}
-		}
-	}
-
-	// 생성자 패턴 (ClassName(params))
-	if tokens[i].Type == TokenIdentifier && 
-		i+1 < len(tokens) && tokens[i+1].Type == TokenPunctuation && tokens[i+1].Value == "(" {
-		return true
-	}
-
-	// 연산자 오버로드 패턴 (operator Type())
-	if tokens[i].Type == TokenKeyword && tokens[i].Value == "operator" {
-		return true
-	}
-
-	return false
-}
-
-// getMethodName은 메서드 이름을 추출합니다.
-func getMethodName(tokens []Token, pos int) string {
-	// 제어자 건너뛰기 (public, private, static 등)
-	i := pos
-	for i < len(tokens) && tokens[i].Type == TokenKeyword && isModifier(tokens[i].Value) {
-		i++
-	}
-	
-	if i+1 >= len(tokens) {
-		return ""
-	}
-	
-	// 기본 메서드 패턴 (ReturnType Name(...))
-	if ((tokens[i].Type == TokenIdentifier || tokens[i].Type == TokenKeyword) && 
-		tokens[i+1].Type == TokenIdentifier) {
-		return tokens[i+1].Value
-	}
-	
-	// 생성자 패턴 (ClassName(...))
-	if tokens[i].Type == TokenIdentifier && 
-		i+1 < len(tokens) && tokens[i+1].Type == TokenPunctuation && tokens[i+1].Value == "(" {
-		return tokens[i].Value
-	}
-	
-	// 연산자 오버로드 패턴 (operator Type(...))
-	if tokens[i].Type == TokenKeyword && tokens[i].Value == "operator" && i+1 < len(tokens) {
-		return "operator" + tokens[i+1].Value
-	}
-	
-	return ""
-}
-
 // isModifier는 주어진 키워드가 접근 제한자인지 확인합니다.
 func isModifier(word string) bool {
 	modifiers := map[string]bool{
@@ -691,11 +1436,18 @@ func isModifier(word string) bool {
 		"unsafe":    true,
 		"new":       true,
 		"partial":   true,
+		"implicit":  true,
+		"explicit":  true,
 	}
 	
 	return modifiers[word]
 }
 
+// GetName은 파서의 등록 이름을 반환합니다.
+func (p *CSharpParser) GetName() string {
+	return "csharp_parser"
+}
+
 // GetLanguage는 파서가 처리하는 언어를 반환합니다.
 func (p *CSharpParser) GetLanguage() string {
 	return "C#"
@@ -708,7 +1460,7 @@ func (p *CSharpParser) GetFileExtensions() []string {
 
 // 유틸리티 함수들
 func isLetter(ch rune) bool {
-	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+	return unicode.IsLetter(ch)
 }
 
 func isDigit(ch rune) bool {
@@ -720,7 +1472,7 @@ func isOperator(ch rune) bool {
 }
 
 func isPunctuation(ch rune) bool {
-	return strings.ContainsRune("(){}[];,.", ch)
+	return strings.ContainsRune("(){}[];,.:", ch)
 }
 
 func isWhitespace(ch rune) bool {
@@ -739,8 +1491,19 @@ func needsSpace(current, next Token) bool {
 
 // methodInfo는 메서드 정보를 저장하는 구조체입니다.
 type methodInfo struct {
-	name     string
-	startPos int
-	endPos   int
-	content  string  // 직접 추출한 메서드 내용
+	name      string
+	startPos  int
+	endPos    int
+	content   string       // 직접 추출한 메서드 내용
+	rng       *model.Range // 청크 텍스트의 원본 내 범위
+	declRange *model.Range // 선언부터 본문 끝까지의 범위
+
+	signature  string
+	parameters []model.Parameter
+	returnType string
+	visibility string
+	modifiers  []string
+	doc        *model.DocComment
+	attributes []string
+	calls      []model.CallSite
 } 
\ No newline at end of file
@@ -0,0 +1,373 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// detectionHeadSize는 내용 기반 판별을 위해 읽는 파일 앞부분(모드라인은 끝부분도)의 최대 크기입니다.
+const detectionHeadSize = 8 * 1024
+
+// modelineLines는 모드라인을 찾는 파일 앞과 뒤의 줄 수입니다.
+const modelineLines = 5
+
+// DetectionConfig는 확장자만으로 파서를 정할 수 없는 파일의 판별 규칙입니다.
+// 모든 값은 등록된 파서 이름을 가리킵니다.
+type DetectionConfig struct {
+	Filenames map[string]string // 파일 이름 또는 glob 패턴 (Dockerfile, *.Designer.cs)
+	Shebangs  map[string]string // #! 라인의 인터프리터 이름 (node, python)
+	Modelines map[string]string // vim/emacs 모드라인의 언어 이름 (javascript, csharp)
+	Content   []ContentRule     // 내용 정규식 규칙 (모호한 확장자 판별)
+}
+
+// ContentRule은 파일 내용으로 파서를 선택하는 규칙입니다.
+// Extensions가 비어 있으면 확장자가 없는 파일에만 적용됩니다.
+type ContentRule struct {
+	Extensions []string
+	Pattern    string
+	Parser     string
+}
+
+// DefaultDetectionConfig는 기본 제공 파서에 대한 판별 규칙을 반환합니다.
+func DefaultDetectionConfig() DetectionConfig {
+	return DetectionConfig{
+		Filenames: map[string]string{},
+		Shebangs: map[string]string{
+			"node":          "javascript_parser",
+			"nodejs":        "javascript_parser",
+			"deno":          "javascript_parser",
+			"dotnet-script": "csharp_parser",
+		},
+		Modelines: map[string]string{
+			"javascript": "javascript_parser",
+			"js":         "javascript_parser",
+			"csharp":     "csharp_parser",
+			"cs":         "csharp_parser",
+		},
+	}
+}
+
+// detector는 컴파일된 판별 규칙입니다.
+type detector struct {
+	filenames map[string]string
+	globs     []filenameGlob // filenames 중 glob 패턴 (구체적인 패턴부터)
+	shebangs  map[string]string
+	modelines map[string]string
+	content   []compiledContentRule
+}
+
+// filenameGlob은 파일 이름 glob 패턴 규칙입니다.
+type filenameGlob struct {
+	pattern string
+	parser  string
+	literal int // 와일드카드가 아닌 글자 수
+}
+
+type compiledContentRule struct {
+	extensions []string
+	pattern    *regexp.Regexp
+	parser     string
+}
+
+var (
+	vimModelinePattern   = regexp.MustCompile(`(?:vi|vim|ex):.*?(?:ft|filetype|syntax)=([\w#+-]+)`)
+	emacsModelinePattern = regexp.MustCompile(`-\*-.*?(?:mode:\s*)?([\w#+-]+)\s*(?:;.*)?-\*-`)
+)
+
+// ConfigureDetection은 판별 규칙을 적용합니다. 설정 값은 기본 규칙에 덧붙여지며 같은 키는 덮어씁니다.
+// 설정에 등록되지 않은 파서 이름이나 잘못된 정규식이 있으면 오류를 반환합니다.
+func (f *ParserFactory) ConfigureDetection(config DetectionConfig) error {
+	d := &detector{
+		filenames: make(map[string]string),
+		shebangs:  make(map[string]string),
+		modelines: make(map[string]string),
+	}
+
+	// 기본 규칙 중 등록된 파서를 가리키는 것만 사용
+	defaults := DefaultDetectionConfig()
+	for _, pair := range []struct{ from, to map[string]string }{
+		{defaults.Filenames, d.filenames},
+		{defaults.Shebangs, d.shebangs},
+		{defaults.Modelines, d.modelines},
+	} {
+		for key, name := range pair.from {
+			if _, exists := f.nameToParser[name]; exists {
+				pair.to[key] = name
+			}
+		}
+	}
+
+	var unknown []string
+	check := func(name string) {
+		if _, exists := f.nameToParser[name]; !exists {
+			unknown = append(unknown, name)
+		}
+	}
+	for key, name := range config.Filenames {
+		check(name)
+		d.filenames[key] = name
+	}
+	for key, name := range config.Shebangs {
+		check(name)
+		d.shebangs[key] = name
+	}
+	for key, name := range config.Modelines {
+		check(name)
+		d.modelines[strings.ToLower(key)] = name
+	}
+	for _, rule := range config.Content {
+		check(rule.Parser)
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid content detection pattern %q: %w", rule.Pattern, err)
+		}
+		d.content = append(d.content, compiledContentRule{
+			extensions: rule.Extensions,
+			pattern:    pattern,
+			parser:     rule.Parser,
+		})
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown parser names in detection rules: %s (available: %s)",
+			strings.Join(unknown, ", "), strings.Join(f.ParserNames(), ", "))
+	}
+
+	d.globs = sortedFilenameGlobs(d.filenames)
+	f.detector = d
+	return nil
+}
+
+// sortedFilenameGlobs는 파일 이름 규칙 중 glob 패턴을 구체적인 순서로 정렬하여 반환합니다.
+// 규칙은 map이므로 여러 패턴이 맞을 때 결과가 실행마다 달라지지 않도록, 와일드카드가 아닌 글자가 많은 패턴을
+// 먼저 확인합니다 (*.Designer.cs가 *.cs보다 우선). 글자 수가 같으면 패턴 문자열 순서를 따릅니다.
+func sortedFilenameGlobs(filenames map[string]string) []filenameGlob {
+	var globs []filenameGlob
+	for pattern, name := range filenames {
+		if strings.ContainsAny(pattern, "*?[") {
+			globs = append(globs, filenameGlob{pattern: pattern, parser: name, literal: globLiteralLength(pattern)})
+		}
+	}
+	sort.Slice(globs, func(i, j int) bool {
+		if globs[i].literal != globs[j].literal {
+			return globs[i].literal > globs[j].literal
+		}
+		return globs[i].pattern < globs[j].pattern
+	})
+	return globs
+}
+
+// globLiteralLength는 glob 패턴에서 와일드카드(*, ?, [...])가 아닌 글자 수를 셉니다.
+func globLiteralLength(pattern string) int {
+	count := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*', '?':
+		case '[':
+			if end := strings.IndexByte(pattern[i:], ']'); end > 0 {
+				i += end
+			}
+		case '\\':
+			i++
+			count++
+		default:
+			count++
+		}
+	}
+	return count
+}
+
+// ParserForFile은 파일 경로와 내용으로 파서를 선택합니다.
+// 파일 이름 규칙이 가장 우선하며, 확장자가 없거나 모호한 경우 shebang, 모드라인, 내용 규칙을 확인한 뒤
+// 마지막으로 확장자 매핑을 사용합니다.
+func (f *ParserFactory) ParserForFile(path string, content []byte) (Parser, error) {
+	ext := filepath.Ext(path)
+	if d := f.detector; d != nil {
+		if name := d.matchFilename(filepath.Base(path)); name != "" {
+			return f.GetParserByName(name)
+		}
+		if content != nil && d.needsContent(ext) {
+			if name := d.matchContent(ext, content); name != "" {
+				return f.GetParserByName(name)
+			}
+		}
+	}
+	return f.GetParser(ext)
+}
+
+// DetectFile은 파일을 분석할 파서를 선택합니다. 내용 확인이 필요한 경우에만 파일 앞부분과 끝부분을 읽습니다.
+func (f *ParserFactory) DetectFile(path string) (Parser, error) {
+	var content []byte
+	if f.detector != nil && f.detector.needsContent(filepath.Ext(path)) {
+		var err error
+		if content, err = readDetectionContent(path); err != nil {
+			return nil, fmt.Errorf("failed to read file for detection: %w", err)
+		}
+	}
+	return f.ParserForFile(path, content)
+}
+
+// needsContent는 확장자만으로 파서를 정할 수 없어 내용을 확인해야 하는지 반환합니다.
+func (d *detector) needsContent(ext string) bool {
+	if ext == "" {
+		return true
+	}
+	for _, rule := range d.content {
+		if containsExtension(rule.extensions, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFilename은 파일 이름 규칙에 맞는 파서 이름을 반환합니다.
+// 정확히 같은 이름의 규칙이 우선하며, 여러 glob 패턴이 맞으면 가장 구체적인 패턴을 사용합니다.
+func (d *detector) matchFilename(base string) string {
+	if name, exists := d.filenames[base]; exists {
+		return name
+	}
+	for _, glob := range d.globs {
+		if matched, _ := filepath.Match(glob.pattern, base); matched {
+			return glob.parser
+		}
+	}
+	return ""
+}
+
+// matchContent는 shebang, 모드라인, 내용 규칙 순서로 파서 이름을 찾습니다.
+// shebang과 내용 규칙은 앞부분만, 모드라인은 앞부분과 끝부분을 확인합니다.
+func (d *detector) matchContent(ext string, content []byte) string {
+	tail := content
+	if len(content) > detectionHeadSize {
+		tail = content[len(content)-detectionHeadSize:]
+		content = content[:detectionHeadSize]
+	}
+	content = bytes.TrimPrefix(content, utf8BOM)
+
+	if ext == "" {
+		if name := d.matchShebang(content); name != "" {
+			return name
+		}
+		if name := d.matchModeline(content, tail); name != "" {
+			return name
+		}
+	}
+
+	for _, rule := range d.content {
+		if (len(rule.extensions) == 0 && ext == "") || containsExtension(rule.extensions, ext) {
+			if rule.pattern.Match(content) {
+				return rule.parser
+			}
+		}
+	}
+	return ""
+}
+
+// matchShebang은 첫 줄의 #! 인터프리터로 파서 이름을 찾습니다. (#!/usr/bin/env node, #!/usr/bin/python3)
+func (d *detector) matchShebang(content []byte) string {
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return ""
+	}
+	line, _, _ := bytes.Cut(content[2:], []byte("\n"))
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+				interpreter = filepath.Base(field)
+				break
+			}
+		}
+	}
+	if name, exists := d.shebangs[interpreter]; exists {
+		return name
+	}
+	// 버전 번호를 뗀 이름으로 다시 확인 (python3.11 -> python)
+	return d.shebangs[strings.TrimRight(interpreter, "0123456789.")]
+}
+
+// matchModeline은 파일 앞부분의 처음 5줄과 끝부분의 마지막 5줄 안의 vim/emacs 모드라인으로 파서 이름을 찾습니다.
+func (d *detector) matchModeline(head, tail []byte) string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(head))
+	for len(lines) < modelineLines && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	tailLines := strings.Split(strings.TrimRight(string(tail), "\r\n"), "\n")
+	if len(tailLines) > modelineLines {
+		tailLines = tailLines[len(tailLines)-modelineLines:]
+	}
+	lines = append(lines, tailLines...)
+
+	for _, line := range lines {
+		for _, pattern := range []*regexp.Regexp{vimModelinePattern, emacsModelinePattern} {
+			if match := pattern.FindStringSubmatch(line); match != nil {
+				if name, exists := d.modelines[strings.ToLower(match[1])]; exists {
+					return name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// containsExtension은 확장자 목록에 주어진 확장자가 있는지 대소문자 구분 없이 확인합니다.
+func containsExtension(extensions []string, ext string) bool {
+	for _, e := range extensions {
+		if strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// readDetectionContent는 파일의 앞부분과, 파일이 더 크면 모드라인을 찾을 끝부분을 읽습니다.
+// 끝부분은 중간을 건너뛰므로 첫 줄(잘린 줄)을 버리고 줄바꿈으로 앞부분과 구분하여 이어 붙입니다.
+func readDetectionContent(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	head := make([]byte, detectionHeadSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+	if n < detectionHeadSize {
+		return head, nil
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(info.Size()-detectionHeadSize, detectionHeadSize)
+	tail := make([]byte, info.Size()-offset)
+	n, err = file.ReadAt(tail, offset)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	tail = tail[:n]
+	if offset > detectionHeadSize {
+		if newline := bytes.IndexByte(tail, '\n'); newline >= 0 {
+			tail = tail[newline+1:]
+		}
+		head = append(head, '\n')
+	}
+	return append(head, tail...), nil
+}
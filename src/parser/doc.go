@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"SkelChunker/src/model"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	xmlSummaryPattern   = regexp.MustCompile(`(?s)<summary>(.*?)</summary>`)
+	xmlParamPattern     = regexp.MustCompile(`(?s)<param\s+name\s*=\s*"([^"]*)"\s*>(.*?)</param>`)
+	xmlReturnsPattern   = regexp.MustCompile(`(?s)<returns>(.*?)</returns>`)
+	xmlReferencePattern = regexp.MustCompile(`<(?:see|seealso|paramref|typeparamref)\s+(?:cref|name|langword)\s*=\s*"([^"]*)"\s*/>`)
+	xmlTagPattern       = regexp.MustCompile(`</?[^>]+>`)
+	jsDocTagPattern     = regexp.MustCompile(`^@(\w+)\s*(?:\{([^}]*)\})?\s*(.*)$`)
+)
+
+// isDocComment는 주석이 문서 주석(///, /** */)인지 확인합니다.
+func isDocComment(comment string) bool {
+	return (strings.HasPrefix(comment, "///") && !strings.HasPrefix(comment, "////")) ||
+		(strings.HasPrefix(comment, "/**") && comment != "/**/")
+}
+
+// parseDocComment는 문서 주석 원문들을 구조화된 DocComment로 변환합니다.
+// XML 태그(<summary> 등)가 있으면 C# XML 문서 주석으로, 아니면 JSDoc 형식으로 해석합니다.
+func parseDocComment(comments []string) *model.DocComment {
+	var lines []string
+	for _, comment := range comments {
+		comment = strings.TrimSpace(comment)
+		comment = strings.TrimSuffix(strings.TrimPrefix(comment, "/**"), "*/")
+		for _, line := range strings.Split(comment, "\n") {
+			line = strings.TrimSpace(line)
+			line = strings.TrimPrefix(line, "///")
+			line = strings.TrimPrefix(line, "*")
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	text := strings.TrimSpace(strings.Join(lines, "\n"))
+	if text == "" {
+		return nil
+	}
+
+	if xmlSummaryPattern.MatchString(text) || xmlParamPattern.MatchString(text) || xmlReturnsPattern.MatchString(text) {
+		return parseXMLDocComment(text)
+	}
+	return parseJSDocComment(lines)
+}
+
+// parseXMLDocComment는 C# XML 문서 주석을 해석합니다.
+func parseXMLDocComment(text string) *model.DocComment {
+	doc := &model.DocComment{}
+	if match := xmlSummaryPattern.FindStringSubmatch(text); match != nil {
+		doc.Summary = cleanXMLDocText(match[1])
+	}
+	for _, match := range xmlParamPattern.FindAllStringSubmatch(text, -1) {
+		doc.Params = append(doc.Params, model.ParamDoc{
+			Name:        match[1],
+			Description: cleanXMLDocText(match[2]),
+		})
+	}
+	if match := xmlReturnsPattern.FindStringSubmatch(text); match != nil {
+		doc.Returns = cleanXMLDocText(match[1])
+	}
+	return doc
+}
+
+// cleanXMLDocText는 XML 문서 주석 본문에서 태그를 제거하고 공백을 정리합니다.
+func cleanXMLDocText(text string) string {
+	text = xmlReferencePattern.ReplaceAllString(text, "$1")
+	text = xmlTagPattern.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+// parseJSDocComment는 JSDoc 형식(@param, @returns)의 문서 주석을 해석합니다.
+func parseJSDocComment(lines []string) *model.DocComment {
+	doc := &model.DocComment{}
+	var summary []string
+	var current *string // 여러 줄에 걸친 태그 설명을 이어 붙일 대상
+
+	for _, line := range lines {
+		match := jsDocTagPattern.FindStringSubmatch(line)
+		if match == nil {
+			if current != nil {
+				*current = strings.TrimSpace(*current + " " + line)
+			} else if line != "" {
+				summary = append(summary, line)
+			}
+			continue
+		}
+
+		current = nil
+		tag, typ, rest := match[1], match[2], match[3]
+		switch tag {
+		case "param", "arg", "argument":
+			name, description, _ := strings.Cut(rest, " ")
+			name = strings.Trim(name, "[]")
+			name, _, _ = strings.Cut(name, "=")
+			doc.Params = append(doc.Params, model.ParamDoc{
+				Name:        name,
+				Type:        typ,
+				Description: strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(description), "-")),
+			})
+			current = &doc.Params[len(doc.Params)-1].Description
+		case "returns", "return":
+			doc.Returns = strings.TrimSpace(strings.TrimPrefix(rest, "-"))
+			if doc.Returns == "" {
+				doc.Returns = typ
+			}
+			current = &doc.Returns
+		case "description", "summary":
+			summary = append(summary, rest)
+		}
+	}
+
+	doc.Summary = strings.Join(strings.Fields(strings.Join(summary, " ")), " ")
+	return doc
+}
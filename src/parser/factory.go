@@ -1,26 +1,77 @@
 package parser
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 // ParserFactory는 파일 확장자에 따라 적절한 파서를 생성하는 팩토리입니다.
+// 파서는 이름(예: csharp_parser)으로 등록되며, 설정에서 임의의 확장자를 이름으로 연결할 수 있습니다.
 type ParserFactory struct {
 	extensionToParser map[string]Parser
+	nameToParser      map[string]Parser
+	detector          *detector
+	invalidNames      []string // 등록하지 않은 빈 이름이나 중복 이름 (Configure가 오류로 반환)
 }
 
 // NewParserFactory는 새로운 ParserFactory 인스턴스를 생성합니다.
 func NewParserFactory() *ParserFactory {
 	return &ParserFactory{
 		extensionToParser: make(map[string]Parser),
+		nameToParser:      make(map[string]Parser),
 	}
 }
 
-// RegisterParser는 파서를 등록합니다.
+// RegisterParser는 파서를 이름으로 등록하고, 파서가 선언한 기본 확장자에도 연결합니다.
+// 이름이 비어 있거나 이미 등록된 이름이면 등록하지 않고 기록해 두었다가 Configure에서 오류로 반환합니다.
 func (f *ParserFactory) RegisterParser(parser Parser) {
+	name := parser.GetName()
+	if name == "" {
+		f.invalidNames = append(f.invalidNames, fmt.Sprintf("empty name (%s)", parser.GetLanguage()))
+		return
+	}
+	if _, exists := f.nameToParser[name]; exists {
+		f.invalidNames = append(f.invalidNames, "duplicate name "+name)
+		return
+	}
+	f.nameToParser[name] = parser
 	for _, ext := range parser.GetFileExtensions() {
 		f.extensionToParser[ext] = parser
 	}
 }
 
+// Configure는 설정의 확장자-파서 이름 매핑을 적용합니다.
+// 매핑이 주어지면 파서가 선언한 기본 확장자 대신 설정의 확장자만 사용합니다.
+// 등록되지 않은 파서 이름이 있으면 모두 모아 하나의 오류로 반환하며, 이때 매핑은 적용되지 않습니다.
+// 이름이 비어 있거나 중복되어 등록하지 못한 파서가 있어도 오류를 반환합니다.
+func (f *ParserFactory) Configure(parsers map[string]string) error {
+	if len(f.invalidNames) > 0 {
+		return fmt.Errorf("invalid parser names: %s", strings.Join(f.invalidNames, ", "))
+	}
+
+	var unknown []string
+	for ext, name := range parsers {
+		if _, exists := f.nameToParser[name]; !exists {
+			unknown = append(unknown, fmt.Sprintf("%s (%s)", name, ext))
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown parser names: %s (available: %s)",
+			strings.Join(unknown, ", "), strings.Join(f.ParserNames(), ", "))
+	}
+
+	if len(parsers) == 0 {
+		return nil
+	}
+	f.extensionToParser = make(map[string]Parser)
+	for ext, name := range parsers {
+		f.extensionToParser[ext] = f.nameToParser[name]
+	}
+	return nil
+}
+
 // GetParser는 파일 확장자에 맞는 파서를 반환합니다.
 func (f *ParserFactory) GetParser(fileExtension string) (Parser, error) {
 	parser, exists := f.extensionToParser[fileExtension]
@@ -28,4 +79,23 @@ func (f *ParserFactory) GetParser(fileExtension string) (Parser, error) {
 		return nil, fmt.Errorf("no parser registered for extension: %s", fileExtension)
 	}
 	return parser, nil
-} 
\ No newline at end of file
+}
+
+// GetParserByName은 등록된 이름으로 파서를 반환합니다.
+func (f *ParserFactory) GetParserByName(name string) (Parser, error) {
+	parser, exists := f.nameToParser[name]
+	if !exists {
+		return nil, fmt.Errorf("no parser registered with name: %s", name)
+	}
+	return parser, nil
+}
+
+// ParserNames는 등록된 파서 이름을 정렬하여 반환합니다.
+func (f *ParserFactory) ParserNames() []string {
+	names := make([]string, 0, len(f.nameToParser))
+	for name := range f.nameToParser {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"SkelChunker/src/model"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// identifierPattern은 JavaScript와 규칙 기반 파서에서 식별자로 보는 단어입니다.
+var identifierPattern = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+
+// jsKeywords는 식별자에서 제외하는 JavaScript 예약어입니다.
+var jsKeywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "export": true,
+	"extends": true, "finally": true, "for": true, "function": true, "if": true, "import": true,
+	"in": true, "instanceof": true, "new": true, "return": true, "super": true, "switch": true,
+	"this": true, "throw": true, "try": true, "typeof": true, "var": true, "void": true,
+	"while": true, "with": true, "yield": true, "let": true, "static": true, "async": true,
+	"await": true, "of": true, "null": true, "true": true, "false": true, "undefined": true,
+}
+
+// ExtractIdentifiers는 C# 소스의 식별자 토큰 위치를 반환합니다.
+// 파서 상태를 바꾸지 않도록 별도의 토크나이저를 사용합니다.
+func (p *CSharpParser) ExtractIdentifiers(sourceCode string) []model.Identifier {
+	tokenizer := &CSharpParser{content: []byte(sourceCode), line: 1, col: 1}
+	if err := tokenizer.tokenize(); err != nil {
+		return nil
+	}
+
+	var identifiers []model.Identifier
+	for _, token := range tokenizer.tokens {
+		if token.Type != TokenIdentifier {
+			continue
+		}
+		identifiers = append(identifiers, model.Identifier{
+			Name:   token.Value,
+			Line:   token.Line,
+			Column: token.Col,
+			Start:  token.Start,
+			End:    token.End,
+		})
+	}
+	return identifiers
+}
+
+// ExtractIdentifiers는 문자열과 주석을 제외한 JavaScript 소스의 식별자 위치를 반환합니다.
+func (p *JavaScriptParser) ExtractIdentifiers(sourceCode string) []model.Identifier {
+	return findIdentifiers(sourceCode, maskJSSource(sourceCode), jsKeywords)
+}
+
+// ExtractIdentifiers는 설정의 주석/문자열 문법을 제외한 소스의 단어 위치를 반환합니다.
+func (p *RuleParser) ExtractIdentifiers(sourceCode string) []model.Identifier {
+	return findIdentifiers(sourceCode, p.mask(sourceCode), nil)
+}
+
+// findIdentifiers는 가려진 소스에서 식별자를 찾아 원본 기준의 라인과 열을 붙여 반환합니다.
+func findIdentifiers(source, masked string, keywords map[string]bool) []model.Identifier {
+	offsets := lineOffsets(source)
+	var identifiers []model.Identifier
+	for _, match := range identifierPattern.FindAllStringIndex(masked, -1) {
+		name := masked[match[0]:match[1]]
+		if keywords[name] || (match[0] > 0 && isDigitBefore(masked, match[0])) {
+			continue
+		}
+		line := sort.Search(len(offsets), func(i int) bool { return offsets[i] > match[0] }) - 1
+		lineStart := offsets[line]
+		if line == 0 && strings.HasPrefix(source, string(utf8BOM)) {
+			lineStart = len(utf8BOM)
+		}
+		identifiers = append(identifiers, model.Identifier{
+			Name:   name,
+			Line:   line + 1,
+			Column: utf8.RuneCountInString(source[lineStart:match[0]]) + 1,
+			Start:  match[0],
+			End:    match[1],
+		})
+	}
+	return identifiers
+}
+
+// isDigitBefore는 위치 바로 앞이 숫자인지(1e5, 0x1F 같은 숫자 리터럴의 일부인지) 확인합니다.
+func isDigitBefore(source string, pos int) bool {
+	ch := source[pos-1]
+	return ch >= '0' && ch <= '9'
+}
@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"SkelChunker/src/model"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// C# using 지시문 (global using, using static, using Alias = ...)
+	csharpUsingPattern = regexp.MustCompile(`(?m)^[ \t]*(global[ \t]+)?using[ \t]+(static[ \t]+)?(?:([\w@]+)[ \t]*=[ \t]*)?([\w@.]+(?:<[\w@.,<> \t]*>)?)[ \t]*;`)
+
+	// JavaScript import/export ... from, 부수 효과 import, require, 동적 import
+	jsImportPattern        = regexp.MustCompile(`(?m)^[ \t]*import[ \t]+(?:type[ \t]+)?([\w$*{}\s,]+?)\s+from\s*['"]([^'"\n]+)['"]`)
+	jsSideEffectPattern    = regexp.MustCompile(`(?m)^[ \t]*import[ \t]*['"]([^'"\n]+)['"]`)
+	jsExportFromPattern    = regexp.MustCompile(`(?m)^[ \t]*export[ \t]+(?:type[ \t]+)?(\*(?:[ \t]+as[ \t]+[\w$]+)?|\{[^}]*\})\s*from\s*['"]([^'"\n]+)['"]`)
+	jsRequirePattern       = regexp.MustCompile(`(?:(?:const|let|var)[ \t]+([\w$]+|\{[^}]*\})[ \t]*=[ \t]*)?\brequire[ \t]*\([ \t]*['"]([^'"\n]+)['"][ \t]*\)`)
+	jsDynamicImportPattern = regexp.MustCompile(`\bimport[ \t]*\([ \t]*['"]([^'"\n]+)['"][ \t]*\)`)
+)
+
+// ExtractImports는 C# using 지시문을 추출합니다. using 문(using (...))과 using 선언(using var)은 제외됩니다.
+func (p *CSharpParser) ExtractImports(sourceCode string) []model.Import {
+	offsets := lineOffsets(sourceCode)
+	var imports []model.Import
+	for _, match := range csharpUsingPattern.FindAllStringSubmatchIndex(sourceCode, -1) {
+		imp := model.Import{
+			Kind: "using",
+			Path: sourceCode[match[8]:match[9]],
+		}
+		if match[2] >= 0 {
+			imp.Modifiers = append(imp.Modifiers, "global")
+		}
+		if match[4] >= 0 {
+			imp.Modifiers = append(imp.Modifiers, "static")
+		}
+		if match[6] >= 0 {
+			imp.Alias = sourceCode[match[6]:match[7]]
+		}
+		imp.Range = offsetRange(offsets, trimStart(sourceCode, match[0]), match[1])
+		imports = append(imports, imp)
+	}
+	return imports
+}
+
+// ExtractImports는 JavaScript import/export ... from/require/동적 import 구문을 추출합니다.
+func (p *JavaScriptParser) ExtractImports(sourceCode string) []model.Import {
+	offsets := lineOffsets(sourceCode)
+	var imports []model.Import
+	add := func(kind, path, clause string, start, end int) {
+		imp := model.Import{Kind: kind, Path: path, Range: offsetRange(offsets, start, end)}
+		imp.Names, imp.Alias = parseJSImportClause(clause)
+		imports = append(imports, imp)
+	}
+
+	for _, m := range jsImportPattern.FindAllStringSubmatchIndex(sourceCode, -1) {
+		add("import", sourceCode[m[4]:m[5]], sourceCode[m[2]:m[3]], trimStart(sourceCode, m[0]), m[1])
+	}
+	for _, m := range jsSideEffectPattern.FindAllStringSubmatchIndex(sourceCode, -1) {
+		add("import", sourceCode[m[2]:m[3]], "", trimStart(sourceCode, m[0]), m[1])
+	}
+	for _, m := range jsExportFromPattern.FindAllStringSubmatchIndex(sourceCode, -1) {
+		add("export", sourceCode[m[4]:m[5]], sourceCode[m[2]:m[3]], trimStart(sourceCode, m[0]), m[1])
+	}
+	for _, m := range jsRequirePattern.FindAllStringSubmatchIndex(sourceCode, -1) {
+		clause := ""
+		if m[2] >= 0 {
+			clause = sourceCode[m[2]:m[3]]
+		}
+		add("require", sourceCode[m[4]:m[5]], clause, m[0], m[1])
+	}
+	for _, m := range jsDynamicImportPattern.FindAllStringSubmatchIndex(sourceCode, -1) {
+		add("import", sourceCode[m[2]:m[3]], "", m[0], m[1])
+	}
+
+	// 패턴별로 찾았으므로 소스 순서로 정렬
+	sort.SliceStable(imports, func(i, j int) bool {
+		return imports[i].Range.Start < imports[j].Range.Start
+	})
+	return imports
+}
+
+// parseJSImportClause는 import 절에서 가져온 이름과 별칭을 추출합니다.
+// (X → names [X], { a, b as c } → names [a, b], * as ns → alias ns)
+func parseJSImportClause(clause string) ([]string, string) {
+	var names []string
+	alias := ""
+	clause = strings.TrimSpace(clause)
+
+	if open := strings.Index(clause, "{"); open >= 0 {
+		close := strings.LastIndex(clause, "}")
+		if close < open {
+			close = len(clause)
+		}
+		for _, part := range strings.Split(clause[open+1:close], ",") {
+			fields := strings.Fields(part)
+			if len(fields) > 0 && fields[0] != "type" {
+				names = append(names, fields[0])
+			} else if len(fields) > 1 {
+				names = append(names, fields[1])
+			}
+		}
+		clause = clause[:open] + clause[close:]
+	}
+
+	for _, part := range strings.Split(clause, ",") {
+		part = strings.Trim(strings.TrimSpace(part), "}")
+		switch {
+		case part == "":
+		case strings.HasPrefix(part, "*"):
+			if fields := strings.Fields(part); len(fields) == 3 && fields[1] == "as" {
+				alias = fields[2]
+			}
+		default:
+			names = append([]string{part}, names...)
+		}
+	}
+	return names, alias
+}
+
+// trimStart는 라인 시작 공백을 건너뛴 오프셋을 반환합니다.
+func trimStart(source string, start int) int {
+	for start < len(source) && (source[start] == ' ' || source[start] == '\t' || source[start] == '\n' || source[start] == '\r') {
+		start++
+	}
+	return start
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"SkelChunker/src/model"
+	"regexp"
 	"strings"
 )
 
@@ -23,8 +24,9 @@ func (p *JavaScriptParser) Parse(sourceCode string) ([]model.SkeletonNode, []mod
 	
 	// 기본 청크는 전체 파일 내용
 	basicChunk := model.Chunk{
-		MD5:  contentMD5,
-		Text: sourceCode,
+		MD5:   contentMD5,
+		Text:  sourceCode,
+		Range: FileRange(sourceCode),
 	}
 	
 	// 스켈레톤 노드 구성 (간단히 함수와 클래스만 검출)
@@ -32,9 +34,15 @@ func (p *JavaScriptParser) Parse(sourceCode string) ([]model.SkeletonNode, []mod
 	
 	// 라인 단위로 분리
 	lines := strings.Split(sourceCode, "\n")
+	offsets := lineOffsets(sourceCode)
+	
+	// 문자열과 주석을 가린 소스 (본문 범위와 호출 검출용)
+	masked := maskJSSource(sourceCode)
+	maskedLines := strings.Split(masked, "\n")
 	
 	for i := 0; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
+		// export/default/async 접두어는 선언 검출에서 제외
+		line := stripJSDeclarationPrefix(strings.TrimSpace(lines[i]))
 		
 		// 함수 정의 검출
 		if strings.HasPrefix(line, "function ") || 
@@ -42,12 +50,22 @@ func (p *JavaScriptParser) Parse(sourceCode string) ([]model.SkeletonNode, []mod
 		   strings.Contains(line, "=> {") {
 			// 함수명 추출 (간단한 구현)
 			functionName := extractFunctionName(line)
+			signature, params, modifiers := parseJSDeclaration(strings.TrimSpace(lines[i]))
+			decorators, doc := jsLeadingDecorations(lines, i)
+			endLine := jsBlockEnd(maskedLines, i)
 			
 			// 스켈레톤 노드에 추가
 			nodes = append(nodes, model.SkeletonNode{
-				Type: "function",
-				Name: functionName,
-				MD5:  contentMD5,
+				Type:       "function",
+				Name:       functionName,
+				MD5:        contentMD5,
+				Range:      lineRange(offsets, len(sourceCode), i, endLine),
+				Signature:  signature,
+				Parameters: params,
+				Modifiers:  modifiers,
+				Doc:        doc,
+				Attributes: decorators,
+				Calls:      findJSCalls(masked, offsets, offsets[i]+jsBodyStart(maskedLines[i]), lineRange(offsets, len(sourceCode), i, endLine).End),
 			})
 		}
 		
@@ -57,34 +75,63 @@ func (p *JavaScriptParser) Parse(sourceCode string) ([]model.SkeletonNode, []mod
 			className := extractClassName(line)
 			
 			// 클래스 노드 생성
+			decorators, doc := jsLeadingDecorations(lines, i)
+			extends, implements := extractClassHeritage(line)
 			classNode := model.SkeletonNode{
-				Type:    "class",
-				Name:    className,
-				Members: []model.Member{},
+				Type:       "class",
+				Name:       className,
+				Members:    []model.Member{},
+				Doc:        doc,
+				Attributes: decorators,
+				Kind:       "class",
+				Signature:  strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(lines[i]), "{")),
+				Extends:    extends,
+				Implements: implements,
 			}
 			
-			// 클래스 내 메서드 검출 (간단한 구현)
+			// 클래스 내 메서드 검출 (간단한 구현, 중괄호는 문자열과 주석을 가린 줄에서 셈)
+			classEndLine := len(lines) - 1
+			depth := strings.Count(maskedLines[i], "{") - strings.Count(maskedLines[i], "}")
 			for j := i + 1; j < len(lines); j++ {
 				methodLine := strings.TrimSpace(lines[j])
+				lineDepth := depth
+				depth += strings.Count(maskedLines[j], "{") - strings.Count(maskedLines[j], "}")
 				
 				// 클래스 끝 검출
-				if methodLine == "}" {
+				if depth <= 0 && strings.Contains(maskedLines[j], "}") {
+					classEndLine = j
 					break
 				}
 				
-				// 메서드 정의 검출
-				if strings.Contains(methodLine, "(") && strings.Contains(methodLine, ")") &&
-				   !strings.HasPrefix(methodLine, "//") {
+				// 메서드 정의 검출 (클래스 본문 바로 아래 깊이만)
+				if lineDepth == 1 && strings.Contains(methodLine, "(") && strings.Contains(methodLine, ")") &&
+				   !strings.HasPrefix(methodLine, "//") && !strings.HasPrefix(methodLine, "@") {
 					methodName := extractMethodName(methodLine)
+					signature, params, modifiers := parseJSDeclaration(methodLine)
+					methodDecorators, methodDoc := jsLeadingDecorations(lines, j)
+					visibility := "public"
+					if strings.HasPrefix(methodName, "#") {
+						visibility = "private"
+					}
+					methodRange := lineRange(offsets, len(sourceCode), j, jsBlockEnd(maskedLines, j))
 					
 					// 메서드를 멤버로 추가
 					classNode.Members = append(classNode.Members, model.Member{
-						Type: "method",
-						Name: methodName,
-						MD5:  calculateMD5(methodLine),
+						Type:       "method",
+						Name:       methodName,
+						MD5:        calculateMD5(methodLine),
+						Range:      methodRange,
+						Signature:  signature,
+						Parameters: params,
+						Visibility: visibility,
+						Modifiers:  modifiers,
+						Doc:        methodDoc,
+						Attributes: methodDecorators,
+						Calls:      findJSCalls(masked, offsets, offsets[j]+jsBodyStart(maskedLines[j]), methodRange.End),
 					})
 				}
 			}
+			classNode.Range = lineRange(offsets, len(sourceCode), i, classEndLine)
 			
 			// 스켈레톤 노드에 클래스 추가
 			nodes = append(nodes, classNode)
@@ -116,16 +163,59 @@ func extractFunctionName(line string) string {
 		// 화살표 함수 또는 함수 표현식
 		parts := strings.Split(line, "=")
 		if len(parts) >= 1 {
-			return strings.TrimSpace(parts[0])
+			name := strings.TrimSpace(parts[0])
+			for _, declarator := range []string{"const ", "let ", "var "} {
+				name = strings.TrimSpace(strings.TrimPrefix(name, declarator))
+			}
+			return name
 		}
 	}
 	
 	return "anonymous"
 }
 
+// jsLeadingDecorations는 선언 라인 바로 위의 데코레이터(@...)와 JSDoc 주석(/** */)을 찾습니다.
+func jsLeadingDecorations(lines []string, index int) ([]string, *model.DocComment) {
+	var decorators []string
+	i := index - 1
+	for ; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "@") {
+			break
+		}
+		decorators = append([]string{strings.TrimPrefix(line, "@")}, decorators...)
+	}
+
+	// JSDoc 블록은 */로 끝나는 라인부터 /**로 시작하는 라인까지
+	if i < 0 || !strings.HasSuffix(strings.TrimSpace(lines[i]), "*/") {
+		return decorators, nil
+	}
+	end := i
+	for ; i >= 0 && !strings.HasPrefix(strings.TrimSpace(lines[i]), "/*"); i-- {
+	}
+	if i < 0 || !isDocComment(strings.TrimSpace(lines[i])) {
+		return decorators, nil
+	}
+	return decorators, parseDocComment([]string{strings.Join(lines[i:end+1], "\n")})
+}
+
+// stripJSDeclarationPrefix는 선언 앞의 export/default/async 키워드를 제거합니다.
+func stripJSDeclarationPrefix(line string) string {
+	for {
+		trimmed := line
+		for _, prefix := range []string{"export ", "default ", "async "} {
+			trimmed = strings.TrimPrefix(trimmed, prefix)
+		}
+		if trimmed == line {
+			return line
+		}
+		line = strings.TrimSpace(trimmed)
+	}
+}
+
 // 클래스명 추출
 func extractClassName(line string) string {
-	parts := strings.Split(line, " ")
+	parts := strings.Fields(strings.Replace(line, "{", " ", 1))
 	if len(parts) >= 2 {
 		className := parts[1]
 		// extends 부분 제거
@@ -137,13 +227,35 @@ func extractClassName(line string) string {
 	return "UnknownClass"
 }
 
+// extractClassHeritage는 클래스 선언 라인에서 extends/implements 목록을 추출합니다.
+func extractClassHeritage(line string) ([]string, []string) {
+	line = strings.TrimSpace(strings.SplitN(line, "{", 2)[0])
+
+	var extends, implements []string
+	var current *[]string
+	for _, word := range strings.Fields(strings.ReplaceAll(line, ",", " , ")) {
+		switch word {
+		case "extends":
+			current = &extends
+		case "implements":
+			current = &implements
+		case ",":
+		default:
+			if current != nil {
+				*current = append(*current, word)
+			}
+		}
+	}
+	return extends, implements
+}
+
 // 메서드명 추출
 func extractMethodName(line string) string {
 	// 주석, 공백 제거
 	line = strings.TrimSpace(line)
 	
 	// 접근 제한자 등 제거
-	keywords := []string{"static", "async", "public", "protected", "private"}
+	keywords := []string{"static", "async", "get", "set", "public", "protected", "private"}
 	for _, keyword := range keywords {
 		if strings.HasPrefix(line, keyword+" ") {
 			line = strings.TrimSpace(line[len(keyword):])
@@ -159,6 +271,62 @@ func extractMethodName(line string) string {
 	return "unknown"
 }
 
+// parseJSDeclaration은 선언 라인에서 시그니처, 매개변수, 제어자를 추출합니다.
+func parseJSDeclaration(line string) (string, []model.Parameter, []string) {
+	signature := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), "{"))
+
+	// 괄호 앞의 제어자 추출
+	head := signature
+	parenIndex := strings.Index(signature, "(")
+	if parenIndex >= 0 {
+		head = signature[:parenIndex]
+	}
+	var modifiers []string
+	for _, word := range strings.Fields(head) {
+		switch word {
+		case "export", "default", "static", "async", "get", "set":
+			modifiers = append(modifiers, word)
+		}
+	}
+	if parenIndex < 0 {
+		return signature, nil, modifiers
+	}
+
+	// 괄호 안의 매개변수 추출
+	depth := 0
+	paramStart := parenIndex + 1
+	var params []model.Parameter
+	for i := parenIndex; i < len(signature); i++ {
+		switch signature[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		}
+		if (depth == 1 && signature[i] == ',') || depth == 0 {
+			param := strings.TrimSpace(signature[paramStart:i])
+			if param != "" {
+				name, value, _ := strings.Cut(param, "=")
+				params = append(params, model.Parameter{
+					Name:    strings.TrimSpace(name),
+					Default: strings.TrimSpace(value),
+				})
+			}
+			paramStart = i + 1
+		}
+		if depth == 0 {
+			break
+		}
+	}
+
+	return strings.TrimSpace(signature), params, modifiers
+}
+
+// GetName은 파서의 등록 이름을 반환합니다.
+func (p *JavaScriptParser) GetName() string {
+	return "javascript_parser"
+}
+
 // GetLanguage는 파서가 처리하는 언어를 반환합니다.
 func (p *JavaScriptParser) GetLanguage() string {
 	return "JavaScript"
@@ -168,3 +336,179 @@ func (p *JavaScriptParser) GetLanguage() string {
 func (p *JavaScriptParser) GetFileExtensions() []string {
 	return []string{".js"}
 }
+
+// jsCallPattern은 호출 이름과 앞의 수신 객체(obj. / obj?.)를 찾는 정규식입니다.
+var jsCallPattern = regexp.MustCompile(`(?:([\w$]+)\s*\??\.\s*)?([\w$#]+)\s*\(`)
+
+// jsNonCallWords는 괄호가 뒤따르지만 호출이 아닌 키워드입니다.
+var jsNonCallWords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true, "function": true,
+	"return": true, "typeof": true, "await": true, "with": true, "import": true,
+}
+
+// findJSCalls는 가려진 소스의 start~end 범위에서 함수 호출과 생성자 호출을 찾습니다.
+func findJSCalls(masked string, offsets []int, start, end int) []model.CallSite {
+	if start >= end {
+		return nil
+	}
+	var calls []model.CallSite
+	for _, m := range jsCallPattern.FindAllStringSubmatchIndex(masked[start:end], -1) {
+		name := masked[start+m[4] : start+m[5]]
+		if jsNonCallWords[name] {
+			continue
+		}
+		call := model.CallSite{
+			Name:  name,
+			Range: offsetRange(offsets, start+m[4], start+m[5]),
+		}
+		before := strings.TrimRight(masked[:start+m[4]], " \t\n")
+		if m[2] >= 0 {
+			call.Receiver = masked[start+m[2] : start+m[3]]
+		} else if strings.HasSuffix(before, ".") {
+			call.Kind = "member"
+		} else if strings.HasSuffix(strings.TrimRight(masked[:start+m[0]], " \t"), "new") {
+			call.Kind = "new"
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// jsBodyStart는 선언 라인에서 본문이 시작하는 위치(=> 또는 매개변수 목록 다음)를 반환합니다.
+func jsBodyStart(line string) int {
+	if index := strings.Index(line, "=>"); index >= 0 {
+		return index + 2
+	}
+	if index := strings.LastIndex(line, ")"); index >= 0 {
+		return index + 1
+	}
+	return len(line)
+}
+
+// jsBlockEnd는 start 라인에서 시작하는 중괄호 블록이 끝나는 라인을 반환합니다.
+// 라인에 여는 중괄호가 없으면(화살표 함수 식 등) 선언 라인에서 끝난 것으로 봅니다.
+func jsBlockEnd(maskedLines []string, start int) int {
+	depth := 0
+	opened := false
+	for i := start; i < len(maskedLines); i++ {
+		for _, ch := range maskedLines[i] {
+			switch ch {
+			case '{':
+				depth++
+				opened = true
+			case '}':
+				depth--
+			}
+			if opened && depth == 0 {
+				return i
+			}
+		}
+		if !opened {
+			return start
+		}
+	}
+	return len(maskedLines) - 1
+}
+
+// maskJSSource는 문자열, 정규식 리터럴과 주석 내용을 공백으로 바꾼 소스를 반환합니다. 바이트 위치와 줄바꿈은 유지됩니다.
+func maskJSSource(source string) string {
+	masked := []byte(source)
+	blank := func(from, to int) {
+		for i := from; i < min(to, len(masked)); i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+
+	last := -1 // 주석과 공백을 제외한 마지막 코드 문자의 위치 (정규식 리터럴 판단용)
+	for i := 0; i < len(source); i++ {
+		switch {
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				end = len(source) - i
+			}
+			blank(i, i+end)
+			i += end - 1
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				// 닫히지 않은 주석은 파일 끝까지
+				blank(i, len(source))
+				i = len(source)
+				continue
+			}
+			blank(i, i+end+4)
+			i += end + 3
+		case source[i] == '/' && jsRegexAllowed(source, last):
+			end := jsRegexEnd(source, i)
+			blank(i+1, end)
+			i, last = end, end
+		case source[i] == '\'' || source[i] == '"' || source[i] == '`':
+			quote := source[i]
+			j := i + 1
+			for ; j < len(source) && source[j] != quote; j++ {
+				if source[j] == '\\' {
+					j++
+				} else if source[j] == '\n' && quote != '`' {
+					break
+				}
+			}
+			blank(i+1, j)
+			i, last = j, j
+		case source[i] != ' ' && source[i] != '\t' && source[i] != '\n' && source[i] != '\r':
+			last = i
+		}
+	}
+	return string(masked)
+}
+
+// jsRegexKeywords는 뒤에 나누기가 아닌 정규식 리터럴이 올 수 있는 키워드입니다.
+var jsRegexKeywords = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true, "of": true, "new": true, "delete": true,
+	"void": true, "throw": true, "case": true, "do": true, "else": true, "yield": true, "await": true,
+}
+
+// jsRegexAllowed는 last 위치의 코드 문자 뒤에 오는 /가 나누기가 아니라 정규식 리터럴의 시작인지 판단합니다.
+func jsRegexAllowed(source string, last int) bool {
+	if last < 0 {
+		return true
+	}
+	if strings.IndexByte("(,=:[!&|?{};+-*%<>~^", source[last]) >= 0 {
+		return true
+	}
+	start := last
+	for start > 0 && isJSIdentChar(source[start-1]) {
+		start--
+	}
+	return isJSIdentChar(source[last]) && jsRegexKeywords[source[start:last+1]]
+}
+
+// jsRegexEnd는 start의 /로 시작하는 정규식 리터럴을 닫는 / 위치를 반환합니다.
+// 문자 클래스([...]) 안의 /와 이스케이프된 /는 건너뛰며, 줄 끝까지 닫히지 않으면 줄 끝 위치를 반환합니다.
+func jsRegexEnd(source string, start int) int {
+	inClass := false
+	for j := start + 1; j < len(source); j++ {
+		switch source[j] {
+		case '\\':
+			j++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				return j
+			}
+		case '\n':
+			return j
+		}
+	}
+	return len(source)
+}
+
+// isJSIdentChar는 JavaScript 식별자에 쓰이는 ASCII 문자인지 확인합니다.
+func isJSIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
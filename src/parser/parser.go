@@ -9,9 +9,24 @@ type Parser interface {
 	// Parse는 소스 코드 문자열을 받아 스켈레톤 노드와 청크들을 반환합니다.
 	Parse(sourceCode string) ([]model.SkeletonNode, []model.Chunk, error)
 	
+	// GetName은 설정에서 파서를 지정할 때 사용하는 등록 이름(예: csharp_parser)을 반환합니다.
+	GetName() string
+	
 	// GetLanguage는 파서가 처리할 수 있는 프로그래밍 언어를 반환합니다.
 	GetLanguage() string
 	
 	// GetFileExtensions는 파서가 처리할 수 있는 파일 확장자들을 반환합니다.
 	GetFileExtensions() []string
-} 
\ No newline at end of file
+}
+
+// ImportExtractor는 소스 코드에서 using/import/require/#include 구문을 추출할 수 있는 파서가 구현합니다.
+type ImportExtractor interface {
+	// ExtractImports는 소스 코드의 의존 구문들을 나타난 순서대로 반환합니다.
+	ExtractImports(sourceCode string) []model.Import
+}
+
+// IdentifierExtractor는 소스 코드에서 식별자 위치를 추출할 수 있는 파서가 구현합니다.
+type IdentifierExtractor interface {
+	// ExtractIdentifiers는 주석과 문자열을 제외한 식별자들을 나타난 순서대로 반환합니다.
+	ExtractIdentifiers(sourceCode string) []model.Identifier
+}
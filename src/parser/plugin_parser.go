@@ -0,0 +1,147 @@
+package parser
+
+import (
+	"SkelChunker/src/model"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// PluginProtocolVersion은 외부 파서 플러그인과 주고받는 JSON 프로토콜의 버전입니다.
+const PluginProtocolVersion = 1
+
+// defaultPluginTimeout은 플러그인 설정에 제한 시간이 없을 때 사용하는 값입니다.
+const defaultPluginTimeout = 30 * time.Second
+
+// pluginStderrLimit은 오류 메시지에 포함할 플러그인 stderr의 최대 길이입니다.
+const pluginStderrLimit = 2048
+
+// PluginConfig는 외부 파서 플러그인 실행 설정입니다.
+type PluginConfig struct {
+	Name       string        // 등록 이름 (예: abap_parser)
+	Language   string        // 처리하는 언어
+	Command    string        // 실행 파일 경로
+	Args       []string      // 실행 인자
+	Extensions []string      // 기본 확장자
+	Timeout    time.Duration // 파일 하나를 파싱하는 제한 시간
+}
+
+// PluginRequest는 플러그인의 stdin으로 전달되는 요청입니다.
+type PluginRequest struct {
+	Version  int    `json:"version"`
+	Language string `json:"language"`
+	Source   string `json:"source"`
+}
+
+// PluginResponse는 플러그인이 stdout으로 돌려주는 응답입니다.
+type PluginResponse struct {
+	Skeleton []model.SkeletonNode `json:"skeleton"`
+	Chunks   []model.Chunk        `json:"chunks"`
+	Error    string               `json:"error,omitempty"`
+}
+
+// PluginParser는 외부 실행 파일에 소스를 넘겨 파싱하는 파서입니다.
+// 파일마다 별도 프로세스를 실행하므로 플러그인이 죽거나 멈춰도 해당 파일만 실패합니다.
+type PluginParser struct {
+	config PluginConfig
+}
+
+// NewPluginParser는 새로운 플러그인 파서를 생성합니다.
+func NewPluginParser(config PluginConfig) *PluginParser {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultPluginTimeout
+	}
+	return &PluginParser{config: config}
+}
+
+// Parse는 플러그인을 실행하여 스켈레톤과 청크를 반환합니다.
+func (p *PluginParser) Parse(sourceCode string) ([]model.SkeletonNode, []model.Chunk, error) {
+	request, err := json.Marshal(PluginRequest{
+		Version:  PluginProtocolVersion,
+		Language: p.config.Language,
+		Source:   sourceCode,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.config.Command, p.config.Args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// 플러그인이 만든 자식 프로세스가 파이프를 잡고 있어도 기다리지 않도록 함
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, nil, fmt.Errorf("plugin %s timed out after %s", p.config.Name, p.config.Timeout)
+		}
+		return nil, nil, fmt.Errorf("plugin %s failed: %w%s", p.config.Name, err, stderrSuffix(stderr.String()))
+	}
+
+	var response PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode plugin %s response: %w%s", p.config.Name, err, stderrSuffix(stderr.String()))
+	}
+	if response.Error != "" {
+		return nil, nil, fmt.Errorf("plugin %s reported an error: %s", p.config.Name, response.Error)
+	}
+
+	fillPluginMD5(response.Skeleton, response.Chunks)
+	return response.Skeleton, response.Chunks, nil
+}
+
+// fillPluginMD5는 플러그인이 생략한 MD5 값을 텍스트 기준으로 채웁니다.
+func fillPluginMD5(nodes []model.SkeletonNode, chunks []model.Chunk) {
+	for i := range chunks {
+		if chunks[i].MD5 == "" {
+			chunks[i].MD5 = calculateMD5(chunks[i].Text)
+		}
+	}
+	for i := range nodes {
+		if nodes[i].MD5 == "" {
+			nodes[i].MD5 = calculateMD5(nodes[i].Signature + nodes[i].Name)
+		}
+		for j := range nodes[i].Members {
+			if nodes[i].Members[j].MD5 == "" {
+				nodes[i].Members[j].MD5 = calculateMD5(nodes[i].Members[j].Signature + nodes[i].Members[j].Name)
+			}
+		}
+	}
+}
+
+// stderrSuffix는 오류 메시지 뒤에 붙일 플러그인 stderr 요약을 만듭니다.
+func stderrSuffix(stderr string) string {
+	stderr = strings.TrimSpace(stderr)
+	if stderr == "" {
+		return ""
+	}
+	if len(stderr) > pluginStderrLimit {
+		stderr = stderr[len(stderr)-pluginStderrLimit:]
+	}
+	return ": " + stderr
+}
+
+// GetName은 설정에 지정된 플러그인 이름을 반환합니다.
+func (p *PluginParser) GetName() string {
+	return p.config.Name
+}
+
+// GetLanguage는 플러그인이 처리하는 언어를 반환합니다.
+func (p *PluginParser) GetLanguage() string {
+	return p.config.Language
+}
+
+// GetFileExtensions는 플러그인의 기본 확장자들을 반환합니다.
+func (p *PluginParser) GetFileExtensions() []string {
+	return p.config.Extensions
+}
@@ -0,0 +1,132 @@
+; 선언: @definition.<type> 은 선언 전체, @name 은 선언 이름
+(class_declaration
+  name: (identifier) @name) @definition.class
+
+(interface_declaration
+  name: (identifier) @name) @definition.interface
+
+(struct_declaration
+  name: (identifier) @name) @definition.struct
+
+(record_declaration
+  name: (identifier) @name) @definition.record
+
+(enum_declaration
+  name: (identifier) @name) @definition.enum
+
+(method_declaration
+  name: (identifier) @name) @definition.method
+
+(constructor_declaration
+  name: (identifier) @name) @definition.constructor
+
+(property_declaration
+  name: (identifier) @name) @definition.property
+
+; 제어자: @modifier 는 선언의 제어자 (public, partial, static 등), 같은 선언의 여러 매치는 하나로 합침
+(class_declaration
+  (modifier) @modifier
+  name: (identifier) @name) @definition.class
+
+(interface_declaration
+  (modifier) @modifier
+  name: (identifier) @name) @definition.interface
+
+(struct_declaration
+  (modifier) @modifier
+  name: (identifier) @name) @definition.struct
+
+(record_declaration
+  (modifier) @modifier
+  name: (identifier) @name) @definition.record
+
+(enum_declaration
+  (modifier) @modifier
+  name: (identifier) @name) @definition.enum
+
+(method_declaration
+  (modifier) @modifier
+  name: (identifier) @name) @definition.method
+
+(constructor_declaration
+  (modifier) @modifier
+  name: (identifier) @name) @definition.constructor
+
+(property_declaration
+  (modifier) @modifier
+  name: (identifier) @name) @definition.property
+
+; 호출: @reference.call 은 호출 식 전체, @name 은 호출 이름, @receiver 는 수신 객체
+;       @reference.new 는 생성자 호출
+(invocation_expression
+  function: [
+    (identifier) @name
+    (generic_name (identifier) @name)
+  ]) @reference.call
+
+(invocation_expression
+  function: (member_access_expression
+    expression: _ @receiver
+    name: [
+      (identifier) @name
+      (generic_name (identifier) @name)
+    ])) @reference.call
+
+(object_creation_expression
+  type: [
+    (identifier) @name
+    (generic_name (identifier) @name)
+    (qualified_name name: (identifier) @name)
+  ]) @reference.new
+
+; 상속: C# 상속 목록은 기본 클래스와 인터페이스를 구분하지 않으므로, 내장 C# 파서처럼
+;       클래스와 레코드의 첫 번째 타입이 IName 형식이 아니면 @extends, IName 형식이면 @implements 로 캡처
+(class_declaration
+  name: (identifier) @name
+  (base_list
+    .
+    (_) @extends
+    (#not-match? @extends "^([A-Za-z_][A-Za-z0-9_]*\\.)*I[A-Z]"))) @definition.class
+
+(class_declaration
+  name: (identifier) @name
+  (base_list
+    (_) @implements
+    (#match? @implements "^([A-Za-z_][A-Za-z0-9_]*\\.)*I[A-Z]"))) @definition.class
+
+(record_declaration
+  name: (identifier) @name
+  (base_list
+    .
+    (_) @extends
+    (#not-match? @extends "^([A-Za-z_][A-Za-z0-9_]*\\.)*I[A-Z]"))) @definition.record
+
+(record_declaration
+  name: (identifier) @name
+  (base_list
+    (_) @implements
+    (#match? @implements "^([A-Za-z_][A-Za-z0-9_]*\\.)*I[A-Z]"))) @definition.record
+
+(struct_declaration
+  name: (identifier) @name
+  (base_list
+    (_) @implements)) @definition.struct
+
+(interface_declaration
+  name: (identifier) @name
+  (base_list
+    (_) @extends)) @definition.interface
+
+; 의존 구문: @import.<kind> 는 구문 전체, @path 는 가져오는 경로, @imported 는 가져온 이름,
+;           @alias 는 별칭, @modifier 는 제어자 (같은 구문의 여러 매치는 하나로 합침)
+(using_directive
+  (_) @path .) @import.using
+
+(using_directive
+  name: (identifier) @alias) @import.using
+
+(using_directive
+  "global" @modifier) @import.using
+
+(using_directive
+  "static" @modifier) @import.using
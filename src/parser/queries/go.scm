@@ -0,0 +1,28 @@
+; 선언: @definition.<type> 은 선언 전체, @name 은 선언 이름
+(type_spec
+  name: (type_identifier) @name
+  type: (struct_type)) @definition.struct
+
+(type_spec
+  name: (type_identifier) @name
+  type: (interface_type)) @definition.interface
+
+(function_declaration
+  name: (identifier) @name) @definition.function
+
+(method_declaration
+  name: (field_identifier) @name) @definition.method
+
+; 호출: @reference.call 은 호출 식 전체, @name 은 호출 이름, @receiver 는 수신 객체
+(call_expression
+  function: (identifier) @name) @reference.call
+
+(call_expression
+  function: (selector_expression
+    operand: (_) @receiver
+    field: (field_identifier) @name)) @reference.call
+
+; 의존 구문: @import.<kind> 는 구문 전체, @path 는 가져오는 경로, @alias 는 별칭
+(import_spec
+  name: (_)? @alias
+  path: (interpreted_string_literal (interpreted_string_literal_content) @path)) @import.import
@@ -0,0 +1,56 @@
+; 선언: @definition.<type> 은 선언 전체, @name 은 선언 이름
+(class_declaration
+  name: (identifier) @name) @definition.class
+
+(interface_declaration
+  name: (identifier) @name) @definition.interface
+
+(enum_declaration
+  name: (identifier) @name) @definition.enum
+
+(record_declaration
+  name: (identifier) @name) @definition.record
+
+(method_declaration
+  name: (identifier) @name) @definition.method
+
+(constructor_declaration
+  name: (identifier) @name) @definition.constructor
+
+; 호출: @reference.call 은 호출 식 전체, @name 은 호출 이름, @receiver 는 수신 객체
+;       @reference.new 는 생성자 호출
+(method_invocation
+  object: (_)? @receiver
+  name: (identifier) @name) @reference.call
+
+(object_creation_expression
+  type: (type_identifier) @name) @reference.new
+
+; 상속: @extends 는 상위 클래스/인터페이스, @implements 는 구현한 인터페이스
+(class_declaration
+  name: (identifier) @name
+  superclass: (superclass
+    (type_identifier) @extends)) @definition.class
+
+(class_declaration
+  name: (identifier) @name
+  interfaces: (super_interfaces
+    (type_list
+      [(type_identifier) (generic_type)] @implements))) @definition.class
+
+(interface_declaration
+  name: (identifier) @name
+  (extends_interfaces
+    (type_list
+      [(type_identifier) (generic_type)] @extends))) @definition.interface
+
+; 의존 구문: @import.<kind> 는 구문 전체, @path 는 가져오는 경로, @imported 는 가져온 이름(*),
+;           @modifier 는 제어자 (같은 구문의 여러 매치는 하나로 합침)
+(import_declaration
+  [(scoped_identifier) (identifier)] @path) @import.import
+
+(import_declaration
+  (asterisk) @imported) @import.import
+
+(import_declaration
+  "static" @modifier) @import.import
@@ -0,0 +1,86 @@
+; 선언: @definition.<type> 은 선언 전체, @name 은 선언 이름
+(class_declaration
+  name: (identifier) @name) @definition.class
+
+(method_definition
+  name: [(property_identifier) (private_property_identifier)] @name) @definition.method
+
+(function_declaration
+  name: (identifier) @name) @definition.function
+
+(generator_function_declaration
+  name: (identifier) @name) @definition.function
+
+; 호출: @reference.call 은 호출 식 전체, @name 은 호출 이름, @receiver 는 수신 객체
+;       @reference.new 는 생성자 호출
+(call_expression
+  function: [(identifier) (super)] @name) @reference.call
+
+(call_expression
+  function: (member_expression
+    object: (_) @receiver
+    property: [(property_identifier) (private_property_identifier)] @name)) @reference.call
+
+(new_expression
+  constructor: (identifier) @name) @reference.new
+
+; 상속: @extends 는 상위 클래스
+(class_declaration
+  name: (identifier) @name
+  (class_heritage
+    (_) @extends)) @definition.class
+
+; 의존 구문: @import.<kind> 는 구문 전체, @path 는 가져오는 경로, @imported 는 가져온 이름,
+;           @alias 는 별칭 (같은 구문의 여러 매치는 하나로 합침)
+(import_statement
+  source: (string (string_fragment) @path)) @import.import
+
+(import_statement
+  (import_clause (identifier) @imported)) @import.import
+
+(import_statement
+  (import_clause
+    (named_imports
+      (import_specifier name: (_) @imported)))) @import.import
+
+(import_statement
+  (import_clause
+    (namespace_import (identifier) @alias))) @import.import
+
+(export_statement
+  source: (string (string_fragment) @path)) @import.export
+
+(export_statement
+  (export_clause
+    (export_specifier name: (_) @imported))
+  source: (_)) @import.export
+
+(export_statement
+  (namespace_export (identifier) @alias)) @import.export
+
+(call_expression
+  function: (identifier) @_require
+  arguments: (arguments . (string (string_fragment) @path))
+  (#eq? @_require "require")) @import.require
+
+(variable_declarator
+  name: (identifier) @imported
+  value: (call_expression
+    function: (identifier) @_require
+    arguments: (arguments . (string))
+    (#eq? @_require "require")) @import.require)
+
+(variable_declarator
+  name: (object_pattern
+    [
+      (shorthand_property_identifier_pattern) @imported
+      (pair_pattern key: (_) @imported)
+    ])
+  value: (call_expression
+    function: (identifier) @_require
+    arguments: (arguments . (string))
+    (#eq? @_require "require")) @import.require)
+
+(call_expression
+  function: (import)
+  arguments: (arguments . (string (string_fragment) @path))) @import.import
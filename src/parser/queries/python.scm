@@ -0,0 +1,45 @@
+; 선언: @definition.<type> 은 선언 전체, @name 은 선언 이름
+(class_definition
+  name: (identifier) @name) @definition.class
+
+(function_definition
+  name: (identifier) @name) @definition.function
+
+; 호출: @reference.call 은 호출 식 전체, @name 은 호출 이름, @receiver 는 수신 객체
+(call
+  function: (identifier) @name) @reference.call
+
+(call
+  function: (attribute
+    object: (_) @receiver
+    attribute: (identifier) @name)) @reference.call
+
+; 상속: @extends 는 기본 클래스 (선언 패턴과 함께 캡처)
+(class_definition
+  name: (identifier) @name
+  superclasses: (argument_list
+    [(identifier) (attribute)] @extends)) @definition.class
+
+; 의존 구문: @import.<kind> 는 구문 전체, @path 는 가져오는 경로, @imported 는 가져온 이름,
+;           @alias 는 별칭 (같은 구문의 여러 매치는 하나로 합침)
+;           import a, b 는 모듈마다 따로 기록하도록 이름 부분을 구문으로 캡처
+(import_statement
+  name: (dotted_name) @path @import.import)
+
+(import_statement
+  name: (aliased_import
+    name: (dotted_name) @path
+    alias: (identifier) @alias) @import.import)
+
+(import_from_statement
+  module_name: (_) @path) @import.import
+
+(import_from_statement
+  name: (dotted_name) @imported) @import.import
+
+(import_from_statement
+  name: (aliased_import
+    name: (dotted_name) @imported)) @import.import
+
+(import_from_statement
+  (wildcard_import) @imported) @import.import
@@ -0,0 +1,446 @@
+package parser
+
+import (
+	"SkelChunker/src/model"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 블록 구분 방식
+const (
+	BlockBraces   = "braces"   // 여는/닫는 괄호 쌍 ({ })
+	BlockIndent   = "indent"   // 들여쓰기 (Python, YAML 스타일)
+	BlockKeywords = "keywords" // 시작/종료 키워드 (Function ... End Function)
+	BlockLine     = "line"     // 선언 한 줄
+)
+
+// RuleParserConfig는 설정만으로 정의하는 규칙 기반 파서의 구성입니다.
+type RuleParserConfig struct {
+	Name            string
+	Language        string
+	Extensions      []string
+	Block           string      // 기본 블록 구분 방식 (기본값: braces)
+	Braces          [2]string   // braces 방식의 여는/닫는 문자열 (기본값: { })
+	CaseInsensitive bool        // 패턴의 대소문자 구분 여부
+	LineComments    []string    // 한 줄 주석 시작 문자열 (//, ', #)
+	BlockComments   [][2]string // 블록 주석 시작/종료 문자열 (/* */)
+	Strings         []string    // 문자열 구분 문자 (", ')
+	StringEscape    string      // 문자열 이스케이프 문자 (비어 있으면 이스케이프 없음)
+	Rules           []RuleConfig
+	Imports         []ImportRuleConfig
+}
+
+// ImportRuleConfig는 의존 구문 하나를 찾는 규칙입니다.
+// Pattern은 라인에 맞는 정규식으로, 경로는 path 그룹 또는 첫 번째 그룹에서 가져옵니다.
+type ImportRuleConfig struct {
+	Kind    string // include, import 등
+	Pattern string
+}
+
+// RuleConfig는 선언 하나를 찾는 규칙입니다.
+// Pattern은 선언이 시작하는 라인에 맞는 정규식으로, 이름은 name 그룹 또는 첫 번째 그룹에서 가져옵니다.
+type RuleConfig struct {
+	Type      string // 노드 타입 (class, function, method 등)
+	Kind      string // 종류 (생략하면 Type과 같음)
+	Pattern   string
+	End       string // keywords 방식의 종료 라인 정규식
+	Block     string // 규칙별 블록 구분 방식 (생략하면 파서 기본값)
+	Container bool   // 멤버를 담는 타입 선언인지 여부
+}
+
+// compiledRule은 정규식을 컴파일한 규칙입니다.
+type compiledRule struct {
+	config  RuleConfig
+	pattern *regexp.Regexp
+	end     *regexp.Regexp
+	block   string
+}
+
+// ruleBlock은 규칙에 맞은 선언 하나의 0-based 라인 범위입니다.
+type ruleBlock struct {
+	rule      *compiledRule
+	name      string
+	signature string
+	startLine int
+	endLine   int
+}
+
+// RuleParser는 설정의 규칙으로 선언을 찾는 범용 파서입니다.
+// 주석과 문자열은 공백으로 가린 뒤 규칙을 적용하므로 그 안의 키워드나 괄호는 무시됩니다.
+type RuleParser struct {
+	config  RuleParserConfig
+	rules   []*compiledRule
+	imports []*regexp.Regexp
+}
+
+// NewRuleParser는 규칙을 검증하고 새로운 규칙 기반 파서를 생성합니다.
+func NewRuleParser(config RuleParserConfig) (*RuleParser, error) {
+	if config.Block == "" {
+		config.Block = BlockBraces
+	}
+	if config.Braces[0] == "" || config.Braces[1] == "" {
+		config.Braces = [2]string{"{", "}"}
+	}
+	if len(config.Rules) == 0 {
+		return nil, fmt.Errorf("rule parser %s has no rules", config.Name)
+	}
+
+	flags := ""
+	if config.CaseInsensitive {
+		flags = "(?i)"
+	}
+
+	p := &RuleParser{config: config}
+	for _, rule := range config.Rules {
+		compiled := &compiledRule{config: rule, block: rule.Block}
+		if compiled.block == "" {
+			compiled.block = config.Block
+		}
+		switch compiled.block {
+		case BlockBraces, BlockIndent, BlockKeywords, BlockLine:
+		default:
+			return nil, fmt.Errorf("rule parser %s: unknown block type %q", config.Name, compiled.block)
+		}
+		if rule.Type == "" {
+			return nil, fmt.Errorf("rule parser %s: rule %q has no type", config.Name, rule.Pattern)
+		}
+
+		var err error
+		if compiled.pattern, err = regexp.Compile(flags + rule.Pattern); err != nil {
+			return nil, fmt.Errorf("rule parser %s: invalid pattern %q: %w", config.Name, rule.Pattern, err)
+		}
+		if compiled.block == BlockKeywords {
+			if rule.End == "" {
+				return nil, fmt.Errorf("rule parser %s: rule %q needs an end pattern", config.Name, rule.Pattern)
+			}
+			if compiled.end, err = regexp.Compile(flags + rule.End); err != nil {
+				return nil, fmt.Errorf("rule parser %s: invalid end pattern %q: %w", config.Name, rule.End, err)
+			}
+		}
+		p.rules = append(p.rules, compiled)
+	}
+
+	for _, rule := range config.Imports {
+		pattern, err := regexp.Compile(flags + rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule parser %s: invalid import pattern %q: %w", config.Name, rule.Pattern, err)
+		}
+		p.imports = append(p.imports, pattern)
+	}
+	return p, nil
+}
+
+// ExtractImports는 import 규칙에 맞는 라인을 의존 구문으로 추출합니다. 주석 안의 라인은 제외됩니다.
+func (p *RuleParser) ExtractImports(sourceCode string) []model.Import {
+	if len(p.imports) == 0 {
+		return nil
+	}
+
+	masked := strings.Split(p.mask(sourceCode), "\n")
+	offsets := lineOffsets(sourceCode)
+	var imports []model.Import
+	for i, line := range strings.Split(sourceCode, "\n") {
+		if strings.TrimSpace(masked[i]) == "" {
+			continue
+		}
+		for j, pattern := range p.imports {
+			match := pattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			path := strings.TrimSpace(match[0])
+			if index := pattern.SubexpIndex("path"); index > 0 && match[index] != "" {
+				path = match[index]
+			} else if len(match) > 1 && match[1] != "" {
+				path = match[1]
+			}
+			imports = append(imports, model.Import{
+				Kind:  p.config.Imports[j].Kind,
+				Path:  path,
+				Range: lineRange(offsets, len(sourceCode), i, i),
+			})
+			break
+		}
+	}
+	return imports
+}
+
+// Parse는 규칙에 맞는 선언을 찾아 스켈레톤과 청크를 반환합니다.
+// 타입(Container) 안의 선언은 멤버가 되고, 타입 밖의 선언은 최상위 노드가 됩니다.
+func (p *RuleParser) Parse(sourceCode string) ([]model.SkeletonNode, []model.Chunk, error) {
+	masked := p.mask(sourceCode)
+	offsets := lineOffsets(sourceCode)
+	lines := strings.Split(masked, "\n")
+	originalLines := strings.Split(sourceCode, "\n")
+
+	var nodes []model.SkeletonNode
+	var chunks []model.Chunk
+	var containers []int // 열려 있는 타입 노드의 nodes 인덱스
+	var containerEnds []int
+
+	for i := 0; i < len(lines); i++ {
+		// 범위를 벗어난 타입 닫기
+		for len(containerEnds) > 0 && i > containerEnds[len(containerEnds)-1] {
+			containers = containers[:len(containers)-1]
+			containerEnds = containerEnds[:len(containerEnds)-1]
+		}
+
+		block, ok := p.matchBlock(lines, originalLines, i)
+		if !ok {
+			continue
+		}
+		rng := lineRange(offsets, len(sourceCode), block.startLine, block.endLine)
+		content := strings.TrimRight(sourceCode[rng.Start:rng.End], "\r")
+		kind := block.rule.config.Kind
+		if kind == "" {
+			kind = block.rule.config.Type
+		}
+
+		if block.rule.config.Container {
+			node := model.SkeletonNode{
+				Type:      block.rule.config.Type,
+				Name:      block.name,
+				MD5:       calculateMD5(content),
+				Range:     rng,
+				Signature: block.signature,
+				Kind:      kind,
+			}
+			if len(containers) > 0 {
+				node.Parent = nodes[containers[len(containers)-1]].QualifiedName()
+			}
+			nodes = append(nodes, node)
+			containers = append(containers, len(nodes)-1)
+			containerEnds = append(containerEnds, block.endLine)
+			continue
+		}
+
+		md5 := calculateMD5(content)
+		if len(containers) > 0 {
+			owner := &nodes[containers[len(containers)-1]]
+			owner.Members = append(owner.Members, model.Member{
+				MD5:       md5,
+				Type:      block.rule.config.Type,
+				Name:      block.name,
+				Range:     rng,
+				Signature: block.signature,
+			})
+		} else {
+			nodes = append(nodes, model.SkeletonNode{
+				Type:      block.rule.config.Type,
+				Name:      block.name,
+				MD5:       md5,
+				Range:     rng,
+				Signature: block.signature,
+				Kind:      kind,
+			})
+		}
+		chunks = append(chunks, model.Chunk{
+			MD5:   md5,
+			Text:  content,
+			Range: rng,
+		})
+
+		// 함수 본문 안의 선언은 찾지 않음
+		i = block.endLine
+	}
+
+	return nodes, chunks, nil
+}
+
+// matchBlock은 라인 i에서 시작하는 선언을 찾아 범위를 계산합니다.
+func (p *RuleParser) matchBlock(lines, originalLines []string, i int) (ruleBlock, bool) {
+	for _, rule := range p.rules {
+		match := rule.pattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+
+		name := strings.TrimSpace(match[0])
+		if index := rule.pattern.SubexpIndex("name"); index > 0 && match[index] != "" {
+			name = match[index]
+		} else if len(match) > 1 && match[1] != "" {
+			name = match[1]
+		}
+
+		return ruleBlock{
+			rule:      rule,
+			name:      name,
+			signature: strings.TrimSpace(originalLines[i]),
+			startLine: i,
+			endLine:   p.findBlockEnd(rule, lines, i),
+		}, true
+	}
+	return ruleBlock{}, false
+}
+
+// findBlockEnd는 규칙의 블록 구분 방식으로 선언이 끝나는 라인을 찾습니다.
+func (p *RuleParser) findBlockEnd(rule *compiledRule, lines []string, start int) int {
+	last := len(lines) - 1
+	for last > start && strings.TrimSpace(lines[last]) == "" {
+		last--
+	}
+
+	switch rule.block {
+	case BlockBraces:
+		open, close := p.config.Braces[0], p.config.Braces[1]
+		depth := 0
+		opened := false
+		for i := start; i <= last; i++ {
+			line := lines[i]
+			for pos := 0; pos < len(line); {
+				switch {
+				case strings.HasPrefix(line[pos:], open):
+					depth++
+					opened = true
+					pos += len(open)
+				case strings.HasPrefix(line[pos:], close):
+					depth--
+					pos += len(close)
+					if opened && depth == 0 {
+						return i
+					}
+				case !opened && line[pos] == ';':
+					// 본문 없는 선언 (추상 메서드, 전방 선언)
+					return i
+				default:
+					pos++
+				}
+			}
+		}
+		return last
+
+	case BlockIndent:
+		indent := indentWidth(lines[start])
+		end := start
+		for i := start + 1; i <= last; i++ {
+			if strings.TrimSpace(lines[i]) == "" {
+				continue
+			}
+			if indentWidth(lines[i]) <= indent {
+				break
+			}
+			end = i
+		}
+		return end
+
+	case BlockKeywords:
+		depth := 1
+		for i := start + 1; i <= last; i++ {
+			if rule.end.MatchString(lines[i]) {
+				depth--
+				if depth == 0 {
+					return i
+				}
+			} else if rule.pattern.MatchString(lines[i]) {
+				depth++
+			}
+		}
+		return last
+	}
+	return start
+}
+
+// indentWidth는 라인의 들여쓰기 폭을 반환합니다. (탭은 4칸)
+func indentWidth(line string) int {
+	width := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// mask는 주석과 문자열 내용을 공백으로 바꾼 소스를 반환합니다. 바이트 위치와 줄바꿈은 그대로 유지됩니다.
+func (p *RuleParser) mask(source string) string {
+	masked := []byte(source)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(masked); i++ {
+			if masked[i] != '\n' && masked[i] != '\r' {
+				masked[i] = ' '
+			}
+		}
+	}
+
+	for pos := 0; pos < len(source); {
+		matched := false
+		for _, comment := range p.config.BlockComments {
+			if comment[0] != "" && strings.HasPrefix(source[pos:], comment[0]) {
+				end := strings.Index(source[pos+len(comment[0]):], comment[1])
+				if end < 0 || comment[1] == "" {
+					end = len(source)
+				} else {
+					end = pos + len(comment[0]) + end + len(comment[1])
+				}
+				blank(pos, end)
+				pos, matched = end, true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		for _, comment := range p.config.LineComments {
+			if comment != "" && strings.HasPrefix(source[pos:], comment) {
+				end := strings.IndexByte(source[pos:], '\n')
+				if end < 0 {
+					end = len(source)
+				} else {
+					end += pos
+				}
+				blank(pos, end)
+				pos, matched = end, true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		for _, quote := range p.config.Strings {
+			if quote != "" && strings.HasPrefix(source[pos:], quote) {
+				end := pos + len(quote)
+				for end < len(source) && source[end] != '\n' {
+					if p.config.StringEscape != "" && strings.HasPrefix(source[end:], p.config.StringEscape) {
+						end += len(p.config.StringEscape) + 1
+						continue
+					}
+					if strings.HasPrefix(source[end:], quote) {
+						break
+					}
+					end++
+				}
+				// 따옴표는 남기고 내용만 가림
+				blank(pos+len(quote), end)
+				pos, matched = end+len(quote), true
+				break
+			}
+		}
+		if !matched {
+			pos++
+		}
+	}
+	return string(masked)
+}
+
+// GetName은 설정에 지정된 파서 이름을 반환합니다.
+func (p *RuleParser) GetName() string {
+	return p.config.Name
+}
+
+// GetLanguage는 파서가 처리하는 언어를 반환합니다.
+func (p *RuleParser) GetLanguage() string {
+	return p.config.Language
+}
+
+// GetFileExtensions는 파서의 기본 확장자들을 반환합니다.
+func (p *RuleParser) GetFileExtensions() []string {
+	return p.config.Extensions
+}
@@ -0,0 +1,556 @@
+//go:build treesitter
+
+package parser
+
+import (
+	"SkelChunker/src/model"
+	"embed"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	tree_sitter_csharp "github.com/tree-sitter/tree-sitter-c-sharp/bindings/go"
+	tree_sitter_go "github.com/tree-sitter/tree-sitter-go/bindings/go"
+	tree_sitter_java "github.com/tree-sitter/tree-sitter-java/bindings/go"
+	tree_sitter_javascript "github.com/tree-sitter/tree-sitter-javascript/bindings/go"
+	tree_sitter_python "github.com/tree-sitter/tree-sitter-python/bindings/go"
+)
+
+//go:embed queries/*.scm
+var treeSitterQueries embed.FS
+
+// treeSitterGrammar는 함께 빌드되는 tree-sitter 문법입니다.
+type treeSitterGrammar struct {
+	language   func() unsafe.Pointer
+	extensions []string
+}
+
+// treeSitterGrammars는 언어 이름별 문법입니다. 언어마다 queries/<언어>.scm 쿼리 파일이 있어야 합니다.
+// C#과 JavaScript는 내장 파서가 기본 확장자를 처리하므로, 설정의 parsers로 확장자를 연결한 경우에만 사용합니다.
+var treeSitterGrammars = map[string]treeSitterGrammar{
+	"csharp":     {tree_sitter_csharp.Language, nil},
+	"go":         {tree_sitter_go.Language, []string{".go"}},
+	"java":       {tree_sitter_java.Language, []string{".java"}},
+	"javascript": {tree_sitter_javascript.Language, nil},
+	"python":     {tree_sitter_python.Language, []string{".py"}},
+}
+
+// treeSitterIdentifier는 수신 객체로 기록할 수 있는 이름입니다.
+var treeSitterIdentifier = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+// treeSitterContainers는 멤버를 담는 타입 선언으로 취급하는 캡처 타입입니다.
+var treeSitterContainers = map[string]bool{
+	"class":     true,
+	"interface": true,
+	"struct":    true,
+	"enum":      true,
+	"record":    true,
+	"trait":     true,
+	"module":    true,
+}
+
+// treeSitterVisibilities는 @modifier 캡처 중 접근 제한자로 기록하는 제어자입니다.
+var treeSitterVisibilities = map[string]bool{
+	"public":    true,
+	"private":   true,
+	"protected": true,
+	"internal":  true,
+}
+
+// TreeSitterParser는 tree-sitter 문법과 쿼리로 선언을 찾는 파서입니다.
+// 쿼리는 @definition.<type> 으로 선언 전체를, @name 으로 선언 이름을 캡처합니다.
+// 타입의 상속 목록은 @extends 와 @implements 로, 제어자는 @modifier 로 캡처하며, 같은 선언의 여러 매치는 하나로 합칩니다.
+// 호출은 @reference.call(생성자 호출은 @reference.new)과 @name, 선택적인 @receiver 로 캡처합니다.
+// 의존 구문은 @import.<kind> 와 @path, 선택적인 @imported, @alias, @modifier 로 캡처합니다.
+// 스켈레톤은 내장 파서와 같은 Type을 사용합니다. 타입 선언은 class(실제 종류는 Kind),
+// 타입 안의 선언은 method, 바깥의 함수는 function 입니다.
+type TreeSitterParser struct {
+	language   string
+	extensions []string
+	grammar    *sitter.Language
+	query      *sitter.Query
+}
+
+// treeSitterDefinition은 쿼리로 찾은 선언 하나입니다.
+type treeSitterDefinition struct {
+	typeName   string
+	name       string
+	signature  string
+	extends    []string
+	implements []string
+	modifiers  map[int]string // 제어자 위치 -> 제어자 (여러 매치에서 합친 뒤 소스 순서로 정렬)
+	start      int
+	end        int
+	startLine  int
+	endLine    int
+}
+
+// visibilityAndModifiers는 캡처한 제어자를 소스 순서로 정렬하여 접근 제한자와 나머지 제어자로 나눕니다.
+// 접근 제한자가 여럿이면(protected internal) 공백으로 이어 붙입니다.
+func (def *treeSitterDefinition) visibilityAndModifiers() (string, []string) {
+	starts := make([]int, 0, len(def.modifiers))
+	for start := range def.modifiers {
+		starts = append(starts, start)
+	}
+	sort.Ints(starts)
+
+	var visibility, modifiers []string
+	for _, start := range starts {
+		if modifier := def.modifiers[start]; treeSitterVisibilities[modifier] {
+			visibility = append(visibility, modifier)
+		} else {
+			modifiers = append(modifiers, modifier)
+		}
+	}
+	return strings.Join(visibility, " "), modifiers
+}
+
+// treeSitterReference는 쿼리로 찾은 호출 하나입니다.
+type treeSitterReference struct {
+	call  model.CallSite
+	start int
+}
+
+// TreeSitterEnabled는 tree-sitter 지원이 함께 빌드되었는지 여부입니다.
+const TreeSitterEnabled = true
+
+// RegisterTreeSitterParsers는 빌드에 포함된 문법마다 treesitter_<언어> 이름으로 파서를 등록합니다.
+// queryFiles로 언어별 쿼리 파일을 내장 쿼리 대신 사용할 수 있습니다.
+func RegisterTreeSitterParsers(factory *ParserFactory, queryFiles map[string]string) error {
+	for language := range queryFiles {
+		if _, exists := treeSitterGrammars[language]; !exists {
+			return fmt.Errorf("no tree-sitter grammar for language: %s", language)
+		}
+	}
+
+	languages := make([]string, 0, len(treeSitterGrammars))
+	for language := range treeSitterGrammars {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	for _, language := range languages {
+		var source []byte
+		var err error
+		if path, exists := queryFiles[language]; exists {
+			source, err = os.ReadFile(path)
+		} else {
+			source, err = treeSitterQueries.ReadFile("queries/" + language + ".scm")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tree-sitter query for %s: %w", language, err)
+		}
+
+		parser, err := NewTreeSitterParser(language, string(source))
+		if err != nil {
+			return err
+		}
+		factory.RegisterParser(parser)
+	}
+	return nil
+}
+
+// NewTreeSitterParser는 언어 이름과 쿼리로 새로운 tree-sitter 파서를 생성합니다.
+func NewTreeSitterParser(language, querySource string) (*TreeSitterParser, error) {
+	grammar, exists := treeSitterGrammars[language]
+	if !exists {
+		return nil, fmt.Errorf("no tree-sitter grammar for language: %s", language)
+	}
+
+	lang := sitter.NewLanguage(grammar.language())
+	query, queryErr := sitter.NewQuery(lang, querySource)
+	if queryErr != nil {
+		return nil, fmt.Errorf("invalid tree-sitter query for %s: %w", language, queryErr)
+	}
+
+	return &TreeSitterParser{
+		language:   language,
+		extensions: grammar.extensions,
+		grammar:    lang,
+		query:      query,
+	}, nil
+}
+
+// Parse는 소스를 구문 트리로 파싱하고 쿼리에 맞는 선언으로 스켈레톤과 청크를 만듭니다.
+// 타입 선언 안의 선언은 멤버가 되며, 함수 본문 안의 선언은 무시합니다.
+func (p *TreeSitterParser) Parse(sourceCode string) ([]model.SkeletonNode, []model.Chunk, error) {
+	source := []byte(sourceCode)
+	tree, err := p.parseTree(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tree.Close()
+
+	definitions, references := p.findDefinitions(tree.RootNode(), source)
+
+	var nodes []model.SkeletonNode
+	var chunks []model.Chunk
+	var containers []int // 열려 있는 타입 노드의 nodes 인덱스
+	skipUntil := -1
+
+	for _, def := range definitions {
+		// 함수 본문 안의 선언은 건너뜀
+		if def.start < skipUntil {
+			continue
+		}
+		// 범위를 벗어난 타입 닫기
+		for len(containers) > 0 && def.start >= nodes[containers[len(containers)-1]].Range.End {
+			containers = containers[:len(containers)-1]
+		}
+
+		rng := &model.Range{StartLine: def.startLine, EndLine: def.endLine, Start: def.start, End: def.end}
+		content := sourceCode[def.start:def.end]
+		md5 := calculateMD5(content)
+		visibility, modifiers := def.visibilityAndModifiers()
+
+		if treeSitterContainers[def.typeName] {
+			node := model.SkeletonNode{
+				Type:       "class",
+				Name:       def.name,
+				MD5:        md5,
+				Range:      rng,
+				Signature:  def.signature,
+				Visibility: visibility,
+				Modifiers:  modifiers,
+				Kind:       def.typeName,
+				Extends:    def.extends,
+				Implements: def.implements,
+			}
+			if len(containers) > 0 {
+				node.Parent = nodes[containers[len(containers)-1]].QualifiedName()
+			}
+			nodes = append(nodes, node)
+			containers = append(containers, len(nodes)-1)
+			continue
+		}
+
+		if len(containers) > 0 {
+			owner := &nodes[containers[len(containers)-1]]
+			owner.Members = append(owner.Members, model.Member{
+				MD5:        md5,
+				Type:       "method",
+				Name:       def.name,
+				Range:      rng,
+				Signature:  def.signature,
+				Visibility: visibility,
+				Modifiers:  modifiers,
+				Calls:      callsWithin(references, def.start, def.end),
+			})
+		} else {
+			nodes = append(nodes, model.SkeletonNode{
+				Type:       "function",
+				Name:       def.name,
+				MD5:        md5,
+				Range:      rng,
+				Signature:  def.signature,
+				Visibility: visibility,
+				Modifiers:  modifiers,
+				Calls:      callsWithin(references, def.start, def.end),
+			})
+		}
+		chunks = append(chunks, model.Chunk{
+			MD5:   md5,
+			Text:  content,
+			Range: rng,
+		})
+		skipUntil = def.end
+	}
+
+	return nodes, chunks, nil
+}
+
+// findDefinitions는 쿼리에 맞는 선언을 소스 순서(바깥 선언 먼저)로, 호출을 소스 순서로 반환합니다.
+func (p *TreeSitterParser) findDefinitions(root *sitter.Node, source []byte) ([]treeSitterDefinition, []treeSitterReference) {
+	cursor := sitter.NewQueryCursor()
+	defer cursor.Close()
+
+	captureNames := p.query.CaptureNames()
+	var definitions []treeSitterDefinition
+	var references []treeSitterReference
+	seen := make(map[[2]int]int) // 선언 범위 -> definitions 인덱스
+	seenReferences := make(map[int]bool)
+
+	matches := cursor.Matches(p.query, root, source)
+	for match := matches.Next(); match != nil; match = matches.Next() {
+		var def treeSitterDefinition
+		var defNode, nameNode *sitter.Node
+		var receiver, referenceKind string
+		var extends, implements []string
+		modifiers := make(map[int]string)
+		for _, capture := range match.Captures {
+			captureName := captureNames[capture.Index]
+			node := capture.Node
+			switch {
+			case captureName == "name":
+				def.name = node.Utf8Text(source)
+				nameNode = &node
+			case captureName == "receiver":
+				receiver = node.Utf8Text(source)
+			case captureName == "extends":
+				extends = append(extends, node.Utf8Text(source))
+			case captureName == "implements":
+				implements = append(implements, node.Utf8Text(source))
+			case captureName == "modifier":
+				modifiers[int(node.StartByte())] = node.Utf8Text(source)
+			case strings.HasPrefix(captureName, "definition."):
+				def.typeName = strings.TrimPrefix(captureName, "definition.")
+				defNode = &node
+			case strings.HasPrefix(captureName, "reference."):
+				referenceKind = strings.TrimPrefix(captureName, "reference.")
+			}
+		}
+		if referenceKind != "" && nameNode != nil {
+			start := int(nameNode.StartByte())
+			if !seenReferences[start] {
+				seenReferences[start] = true
+				references = append(references, treeSitterReference{
+					call:  treeSitterCallSite(def.name, receiver, referenceKind, nameNode),
+					start: start,
+				})
+			}
+			continue
+		}
+		if defNode == nil || def.name == "" {
+			continue
+		}
+
+		def.start, def.end = int(defNode.StartByte()), int(defNode.EndByte())
+		def.startLine = int(defNode.StartPosition().Row) + 1
+		def.endLine = int(defNode.EndPosition().Row) + 1
+		key := [2]int{def.start, def.end}
+		if i, exists := seen[key]; exists {
+			definitions[i].extends = appendMissing(definitions[i].extends, extends...)
+			definitions[i].implements = appendMissing(definitions[i].implements, implements...)
+			for start, modifier := range modifiers {
+				definitions[i].modifiers[start] = modifier
+			}
+			continue
+		}
+		seen[key] = len(definitions)
+		def.signature = treeSitterSignature(defNode, source)
+		def.extends, def.implements, def.modifiers = extends, implements, modifiers
+		definitions = append(definitions, def)
+	}
+
+	sort.SliceStable(definitions, func(i, j int) bool {
+		if definitions[i].start != definitions[j].start {
+			return definitions[i].start < definitions[j].start
+		}
+		return definitions[i].end > definitions[j].end
+	})
+	sort.SliceStable(references, func(i, j int) bool {
+		return references[i].start < references[j].start
+	})
+	return definitions, references
+}
+
+// treeSitterCallSite는 캡처한 호출 이름과 수신 객체로 호출 위치를 만듭니다.
+// 수신 객체가 a.b 처럼 이어진 식이면 마지막 이름만 쓰고, 호출 결과 등 이름이 아니면 member 호출로 기록합니다.
+func treeSitterCallSite(name, receiver, kind string, nameNode *sitter.Node) model.CallSite {
+	if index := strings.LastIndex(receiver, "."); index >= 0 {
+		receiver = receiver[index+1:]
+	}
+	call := model.CallSite{
+		Name:  name,
+		Range: treeSitterRange(nameNode),
+	}
+	switch {
+	case kind == "new":
+		call.Kind = "new"
+	case treeSitterIdentifier.MatchString(receiver):
+		call.Receiver = receiver
+	case receiver != "":
+		call.Kind = "member"
+	}
+	return call
+}
+
+// treeSitterRange는 구문 노드의 범위를 반환합니다.
+func treeSitterRange(node *sitter.Node) *model.Range {
+	return &model.Range{
+		StartLine: int(node.StartPosition().Row) + 1,
+		EndLine:   int(node.EndPosition().Row) + 1,
+		Start:     int(node.StartByte()),
+		End:       int(node.EndByte()),
+	}
+}
+
+// callsWithin은 start~end 범위 안의 호출들을 반환합니다.
+func callsWithin(references []treeSitterReference, start, end int) []model.CallSite {
+	first := sort.Search(len(references), func(i int) bool { return references[i].start >= start })
+	var calls []model.CallSite
+	for i := first; i < len(references) && references[i].start < end; i++ {
+		calls = append(calls, references[i].call)
+	}
+	return calls
+}
+
+// treeSitterSignature는 선언에서 본문(body)이나 접근자 목록(C# 속성의 accessors)을 뺀 앞부분을 한 줄로 만든 시그니처를 반환합니다.
+// 본문이 없는 선언(추상 메서드 등)의 끝 세미콜론도 뺍니다.
+func treeSitterSignature(node *sitter.Node, source []byte) string {
+	end := node.EndByte()
+	for _, field := range []string{"body", "accessors"} {
+		if body := node.ChildByFieldName(field); body != nil {
+			end = body.StartByte()
+			break
+		}
+	}
+	signature := strings.Join(strings.Fields(string(source[node.StartByte():end])), " ")
+	return strings.TrimSpace(strings.TrimRight(signature, "{:;"))
+}
+
+// ExtractIdentifiers는 구문 트리에서 종류가 identifier로 끝나는 노드(type_identifier, field_identifier 등)의 위치를 반환합니다.
+func (p *TreeSitterParser) ExtractIdentifiers(sourceCode string) []model.Identifier {
+	source := []byte(sourceCode)
+	tree, err := p.parseTree(source)
+	if err != nil {
+		return nil
+	}
+	defer tree.Close()
+
+	var identifiers []model.Identifier
+	cursor := tree.Walk()
+	defer cursor.Close()
+	for {
+		node := cursor.Node()
+		if node.ChildCount() == 0 && strings.HasSuffix(node.Kind(), "identifier") {
+			position := node.StartPosition()
+			lineStart := int(node.StartByte()) - int(position.Column)
+			identifiers = append(identifiers, model.Identifier{
+				Name:   node.Utf8Text(source),
+				Line:   int(position.Row) + 1,
+				Column: utf8.RuneCount(source[lineStart:node.StartByte()]) + 1,
+				Start:  int(node.StartByte()),
+				End:    int(node.EndByte()),
+			})
+		}
+		// 깊이 우선 순회: 자식, 형제, 부모의 형제 순
+		if cursor.GotoFirstChild() {
+			continue
+		}
+		for !cursor.GotoNextSibling() {
+			if !cursor.GotoParent() {
+				return identifiers
+			}
+		}
+	}
+}
+
+// ExtractImports는 쿼리의 @import.<kind> 캡처로 찾은 의존 구문을 소스 순서로 반환합니다.
+// 같은 구문에 대한 여러 매치(이름, 별칭, 제어자를 따로 캡처한 패턴)는 하나로 합칩니다.
+func (p *TreeSitterParser) ExtractImports(sourceCode string) []model.Import {
+	source := []byte(sourceCode)
+	tree, err := p.parseTree(source)
+	if err != nil {
+		return nil
+	}
+	defer tree.Close()
+
+	cursor := sitter.NewQueryCursor()
+	defer cursor.Close()
+
+	captureNames := p.query.CaptureNames()
+	var imports []model.Import
+	seen := make(map[[2]int]int) // 구문 범위 -> imports 인덱스
+
+	matches := cursor.Matches(p.query, tree.RootNode(), source)
+	for match := matches.Next(); match != nil; match = matches.Next() {
+		var imp model.Import
+		var importNode *sitter.Node
+		for _, capture := range match.Captures {
+			captureName := captureNames[capture.Index]
+			node := capture.Node
+			switch {
+			case captureName == "path":
+				imp.Path = node.Utf8Text(source)
+			case captureName == "imported":
+				imp.Names = append(imp.Names, node.Utf8Text(source))
+			case captureName == "alias":
+				imp.Alias = node.Utf8Text(source)
+			case captureName == "modifier":
+				imp.Modifiers = append(imp.Modifiers, node.Utf8Text(source))
+			case strings.HasPrefix(captureName, "import."):
+				imp.Kind = strings.TrimPrefix(captureName, "import.")
+				importNode = &node
+			}
+		}
+		if importNode == nil {
+			continue
+		}
+
+		key := [2]int{int(importNode.StartByte()), int(importNode.EndByte())}
+		i, exists := seen[key]
+		if !exists {
+			i = len(imports)
+			seen[key] = i
+			imports = append(imports, model.Import{Kind: imp.Kind, Range: treeSitterRange(importNode)})
+		}
+		merged := &imports[i]
+		if merged.Path == "" {
+			merged.Path = imp.Path
+		}
+		if merged.Alias == "" {
+			merged.Alias = imp.Alias
+		}
+		merged.Names = appendMissing(merged.Names, imp.Names...)
+		merged.Modifiers = appendMissing(merged.Modifiers, imp.Modifiers...)
+	}
+
+	// 경로를 찾지 못한 구문은 제외하고 소스 순서로 정렬
+	var found []model.Import
+	for _, imp := range imports {
+		if imp.Path != "" {
+			found = append(found, imp)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Range.Start < found[j].Range.Start
+	})
+	return found
+}
+
+// parseTree는 소스를 구문 트리로 파싱합니다. 트리는 호출한 쪽에서 닫아야 합니다.
+// sitter.Parser는 동시에 사용할 수 없으므로 호출마다 생성합니다.
+func (p *TreeSitterParser) parseTree(source []byte) (*sitter.Tree, error) {
+	tsParser := sitter.NewParser()
+	defer tsParser.Close()
+	if err := tsParser.SetLanguage(p.grammar); err != nil {
+		return nil, fmt.Errorf("failed to set tree-sitter language: %w", err)
+	}
+	tree := tsParser.Parse(source, nil)
+	if tree == nil {
+		return nil, fmt.Errorf("failed to parse %s source with tree-sitter", p.language)
+	}
+	return tree, nil
+}
+
+// GetName은 파서의 등록 이름(treesitter_<언어>)을 반환합니다.
+func (p *TreeSitterParser) GetName() string {
+	return "treesitter_" + p.language
+}
+
+// GetLanguage는 파서가 처리하는 언어를 반환합니다.
+func (p *TreeSitterParser) GetLanguage() string {
+	return p.language
+}
+
+// GetFileExtensions는 문법의 기본 확장자들을 반환합니다.
+func (p *TreeSitterParser) GetFileExtensions() []string {
+	return p.extensions
+}
+
+// appendMissing은 목록에 없는 값만 순서를 유지하며 추가합니다.
+func appendMissing(list []string, values ...string) []string {
+	for _, value := range values {
+		if !slices.Contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
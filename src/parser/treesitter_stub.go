@@ -0,0 +1,17 @@
+//go:build !treesitter
+
+package parser
+
+import "fmt"
+
+// TreeSitterEnabled는 tree-sitter 지원이 함께 빌드되었는지 여부입니다.
+const TreeSitterEnabled = false
+
+// RegisterTreeSitterParsers는 tree-sitter 없이 빌드된 경우 아무 파서도 등록하지 않습니다.
+// 쿼리 파일이 설정되어 있으면 설정이 무시되지 않도록 오류를 반환합니다.
+func RegisterTreeSitterParsers(factory *ParserFactory, queryFiles map[string]string) error {
+	if len(queryFiles) > 0 {
+		return fmt.Errorf("tree-sitter queries are configured but this build has no tree-sitter support (rebuild with -tags treesitter)")
+	}
+	return nil
+}
@@ -1,12 +1,66 @@
 package parser
 
 import (
+	"SkelChunker/src/model"
 	"crypto/md5"
 	"encoding/hex"
+	"sort"
+	"strings"
 )
 
+// utf8BOM은 UTF-8 BOM(Byte Order Mark) 바이트열입니다.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
 // calculateMD5는 주어진 문자열의 MD5 해시를 계산하여 반환합니다.
 func calculateMD5(content string) string {
 	hash := md5.Sum([]byte(content))
 	return hex.EncodeToString(hash[:])
-} 
\ No newline at end of file
+}
+
+// lineOffsets는 각 라인이 시작하는 바이트 오프셋을 반환합니다. (인덱스 0이 첫 번째 라인)
+func lineOffsets(source string) []int {
+	offsets := []int{0}
+	for i := 0; i < len(source); i++ {
+		if source[i] == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// lineRange는 0-based 라인 범위를 줄바꿈을 제외한 바이트 오프셋 범위로 변환합니다.
+func lineRange(offsets []int, sourceLen int, startLine, endLine int) *model.Range {
+	end := sourceLen
+	if endLine+1 < len(offsets) {
+		end = offsets[endLine+1] - 1
+	}
+	return &model.Range{
+		StartLine: startLine + 1,
+		EndLine:   endLine + 1,
+		Start:     offsets[startLine],
+		End:       end,
+	}
+}
+
+// offsetRange는 바이트 오프셋 범위를 라인 정보가 포함된 범위로 변환합니다.
+func offsetRange(offsets []int, start, end int) *model.Range {
+	lineOf := func(offset int) int {
+		return sort.Search(len(offsets), func(i int) bool { return offsets[i] > offset })
+	}
+	return &model.Range{
+		StartLine: lineOf(start),
+		EndLine:   lineOf(end - 1),
+		Start:     start,
+		End:       end,
+	}
+}
+
+// FileRange는 소스 전체를 가리키는 범위를 반환합니다.
+func FileRange(source string) *model.Range {
+	return &model.Range{
+		StartLine: 1,
+		EndLine:   strings.Count(source, "\n") + 1,
+		Start:     0,
+		End:       len(source),
+	}
+}
@@ -0,0 +1,81 @@
+// markdown_parser는 외부 파서 플러그인 프로토콜의 참조 구현입니다.
+// stdin으로 PluginRequest를 읽고 Markdown 제목(#)마다 section 노드와 청크를 만들어 stdout으로 PluginResponse를 씁니다.
+package main
+
+import (
+	"SkelChunker/src/model"
+	"SkelChunker/src/parser"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// section은 제목 하나와 그 아래 본문의 위치입니다.
+type section struct {
+	title     string
+	level     int
+	startLine int
+	start     int
+}
+
+func main() {
+	var request parser.PluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to decode request: %v\n", err)
+		os.Exit(1)
+	}
+
+	var response parser.PluginResponse
+	if request.Version != parser.PluginProtocolVersion {
+		response.Error = fmt.Sprintf("unsupported protocol version: %d", request.Version)
+	} else {
+		response.Skeleton, response.Chunks = parseMarkdown(request.Source)
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseMarkdown은 제목 단위로 문서를 나눕니다. 코드 블록(```) 안의 # 은 제목으로 보지 않습니다.
+func parseMarkdown(source string) ([]model.SkeletonNode, []model.Chunk) {
+	var sections []section
+	inFence := false
+	offset := 0
+	lines := strings.SplitAfter(source, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		} else if !inFence && strings.HasPrefix(trimmed, "#") {
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			title := strings.TrimSpace(trimmed[level:])
+			if level <= 6 && title != "" {
+				sections = append(sections, section{title: title, level: level, startLine: i + 1, start: offset})
+			}
+		}
+		offset += len(line)
+	}
+
+	var nodes []model.SkeletonNode
+	var chunks []model.Chunk
+	for i, s := range sections {
+		end := len(source)
+		if i+1 < len(sections) {
+			end = sections[i+1].start
+		}
+		text := strings.TrimRight(source[s.start:end], "\r\n")
+		rng := &model.Range{StartLine: s.startLine, EndLine: s.startLine + strings.Count(text, "\n"), Start: s.start, End: s.start + len(text)}
+		nodes = append(nodes, model.SkeletonNode{
+			Type:      "section",
+			Name:      s.title,
+			Kind:      fmt.Sprintf("h%d", s.level),
+			Range:     rng,
+			Signature: strings.Repeat("#", s.level) + " " + s.title,
+		})
+		chunks = append(chunks, model.Chunk{Text: text, Range: rng})
+	}
+	return nodes, chunks
+}
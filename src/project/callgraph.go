@@ -0,0 +1,289 @@
+package project
+
+import (
+	"SkelChunker/src/model"
+	"fmt"
+	"sort"
+)
+
+// CallGraphFileName은 프로젝트 호출 그래프를 저장하는 파일 이름입니다.
+const CallGraphFileName = "callgraph.json"
+
+// constructorNames는 타입 이름 외에 생성자로 취급하는 멤버 이름입니다. (JavaScript, Python)
+var constructorNames = []string{"constructor", "__init__"}
+
+// nonCallableTypes는 호출 대상이 될 수 없는 멤버 타입입니다.
+var nonCallableTypes = map[string]bool{
+	"field":    true,
+	"property": true,
+	"event":    true,
+	"variable": true,
+	"constant": true,
+}
+
+// typeNodeTypes는 멤버를 담는 타입 선언 노드의 타입입니다.
+var typeNodeTypes = map[string]bool{
+	"class":     true,
+	"interface": true,
+	"struct":    true,
+	"record":    true,
+	"enum":      true,
+	"trait":     true,
+	"module":    true,
+}
+
+// CallGraphNode는 호출 그래프의 노드(메서드 또는 함수) 하나입니다.
+// ID는 전체 이름이며, 같은 이름의 선언이 여럿이면(오버로드 등) @파일:라인 을 붙여 구분합니다.
+type CallGraphNode struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Type       string       `json:"type"`
+	Owner      string       `json:"owner,omitempty"` // 멤버가 속한 타입의 전체 이름
+	File       string       `json:"file"`
+	Range      *model.Range `json:"range,omitempty"`
+	Signature  string       `json:"signature,omitempty"`
+	Callees    []string     `json:"callees,omitempty"`
+	Callers    []string     `json:"callers,omitempty"`
+	Unresolved []string     `json:"unresolved,omitempty"` // 프로젝트 안에서 찾지 못한 호출 (외부 라이브러리 등)
+}
+
+// CallGraph는 프로젝트 전체의 호출 관계입니다.
+type CallGraph struct {
+	Nodes []CallGraphNode `json:"nodes"`
+}
+
+// callIndex는 호출 위치를 선언으로 연결하기 위한 프로젝트 색인입니다.
+type callIndex struct {
+	nodes   []CallGraphNode
+	calls   [][]model.CallSite
+	byOwner map[string][]int    // 타입 전체 이름 -> 멤버 노드
+	byFile  map[string][]int    // 파일 -> 타입에 속하지 않은 함수 노드
+	byName  map[string][]int    // 이름 -> 노드
+	imports map[string][]string // 파일 -> 의존 구문이 가리키는 파일들
+	*typeIndex
+}
+
+// BuildCallGraph는 모든 분석 결과의 호출 위치를 프로젝트 안의 메서드와 함수로 연결한 호출 그래프를 만듭니다.
+// ResolveImports 이후에 호출하면 수신 객체의 타입을 알 수 없는 호출을 의존 파일 기준으로 더 정확히 찾습니다.
+func BuildCallGraph(results []*model.AnalysisResult) *CallGraph {
+	index := callIndex{
+		byOwner:   make(map[string][]int),
+		byFile:    make(map[string][]int),
+		byName:    make(map[string][]int),
+		imports:   make(map[string][]string),
+		typeIndex: newTypeIndex(),
+	}
+
+	for _, result := range sortedResults(results) {
+		file := FilePath(result)
+		for _, imp := range result.Imports {
+			index.imports[file] = appendUnique(index.imports[file], imp.Resolved...)
+		}
+		for _, node := range result.Skeleton {
+			if !typeNodeTypes[node.Type] && len(node.Members) == 0 {
+				index.add(CallGraphNode{
+					Name:      node.Name,
+					Type:      node.Type,
+					File:      file,
+					Range:     node.Range,
+					Signature: node.Signature,
+				}, node.Calls)
+				continue
+			}
+
+			owner := index.addType(node)
+			for _, member := range node.Members {
+				if nonCallableTypes[member.Type] {
+					continue
+				}
+				index.add(CallGraphNode{
+					Name:      member.Name,
+					Type:      member.Type,
+					Owner:     owner,
+					File:      file,
+					Range:     member.Range,
+					Signature: member.Signature,
+				}, member.Calls)
+			}
+		}
+	}
+
+	index.assignIDs()
+	for caller := range index.nodes {
+		for _, call := range index.calls[caller] {
+			callees := index.resolve(caller, call)
+			if len(callees) == 0 {
+				name := call.Name
+				if call.Receiver != "" {
+					name = call.Receiver + "." + name
+				}
+				index.nodes[caller].Unresolved = appendUnique(index.nodes[caller].Unresolved, name)
+				continue
+			}
+			for _, callee := range callees {
+				index.nodes[caller].Callees = appendUnique(index.nodes[caller].Callees, index.nodes[callee].ID)
+				index.nodes[callee].Callers = appendUnique(index.nodes[callee].Callers, index.nodes[caller].ID)
+			}
+		}
+	}
+
+	for i := range index.nodes {
+		sort.Strings(index.nodes[i].Callers)
+	}
+	return &CallGraph{Nodes: index.nodes}
+}
+
+// add는 노드와 그 노드의 호출 목록을 색인에 추가합니다.
+func (idx *callIndex) add(node CallGraphNode, calls []model.CallSite) {
+	i := len(idx.nodes)
+	idx.nodes = append(idx.nodes, node)
+	idx.calls = append(idx.calls, calls)
+	idx.byName[node.Name] = append(idx.byName[node.Name], i)
+	if node.Owner != "" {
+		idx.byOwner[node.Owner] = append(idx.byOwner[node.Owner], i)
+	} else {
+		idx.byFile[node.File] = append(idx.byFile[node.File], i)
+	}
+}
+
+// assignIDs는 노드마다 전체 이름으로 ID를 정하고, 겹치는 이름에는 선언 위치를 붙입니다.
+func (idx *callIndex) assignIDs() {
+	count := make(map[string]int)
+	for i := range idx.nodes {
+		count[idx.nodes[i].qualifiedName()]++
+	}
+	for i := range idx.nodes {
+		node := &idx.nodes[i]
+		node.ID = node.qualifiedName()
+		if count[node.ID] > 1 {
+			line := 0
+			if node.Range != nil {
+				line = node.Range.StartLine
+			}
+			node.ID = fmt.Sprintf("%s@%s:%d", node.ID, node.File, line)
+		}
+	}
+}
+
+// qualifiedName은 노드의 전체 이름(타입.멤버 또는 함수 이름)을 반환합니다.
+func (n *CallGraphNode) qualifiedName() string {
+	if n.Owner == "" {
+		return n.Name
+	}
+	return n.Owner + "." + n.Name
+}
+
+// resolve는 caller 노드 안의 호출 하나가 가리키는 노드들을 찾습니다.
+//
+// 1. new Name(...) 과 super(...) 는 해당 타입의 생성자
+// 2. 수신 객체가 없거나 this/self 이면 같은 타입(상위 타입 포함), 같은 파일, 의존 파일 순
+// 3. 수신 객체가 base/super 이면 상위 타입
+// 4. 수신 객체가 타입 이름이면 그 타입(정적 호출)
+// 5. 그 밖의 수신 객체(member 호출 포함)는 같은 파일과 의존 파일, 그래도 없으면 이름이 프로젝트에서 유일할 때만
+func (idx *callIndex) resolve(caller int, call model.CallSite) []int {
+	node := &idx.nodes[caller]
+
+	switch {
+	case call.Kind == "member":
+		return idx.byNameNear(call.Name, node.File)
+	case call.Kind == "new":
+		return idx.constructors(idx.findTypes(call.Name, node.Owner))
+	case call.Receiver == "" && call.Name == "super":
+		return idx.constructors(idx.baseTypes(node.Owner))
+	case call.Receiver == "base" || call.Receiver == "super":
+		return idx.inTypes(call.Name, idx.baseTypes(node.Owner))
+	case call.Receiver == "" || call.Receiver == "this" || call.Receiver == "self":
+		if node.Owner != "" {
+			if found := idx.inTypes(call.Name, []string{node.Owner}); len(found) > 0 {
+				return found
+			}
+		}
+		if call.Receiver != "" {
+			return nil
+		}
+		if found := idx.inFiles(call.Name, node.File, nil); len(found) > 0 {
+			return found
+		}
+		// Python 처럼 new 없이 타입 이름으로 생성하는 호출
+		if found := idx.constructors(idx.findTypes(call.Name, node.Owner)); len(found) > 0 {
+			return found
+		}
+		return idx.inFiles(call.Name, node.File, idx.imports[node.File])
+	}
+
+	if types := idx.findTypes(call.Receiver, node.Owner); len(types) > 0 {
+		return idx.inTypes(call.Name, types)
+	}
+	return idx.byNameNear(call.Name, node.File)
+}
+
+// byNameNear는 수신 객체의 타입을 알 수 없는 호출을 이름으로 찾습니다.
+// 같은 파일과 의존 파일의 선언을 찾고, 없으면 이름이 프로젝트에서 유일할 때만 그 선언을 반환합니다.
+func (idx *callIndex) byNameNear(name, file string) []int {
+	var candidates []int
+	for _, i := range idx.byName[name] {
+		if idx.nodes[i].File == file || contains(idx.imports[file], idx.nodes[i].File) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 && len(idx.byName[name]) == 1 {
+		candidates = idx.byName[name]
+	}
+	return candidates
+}
+
+// inTypes는 주어진 타입들(찾지 못하면 그 상위 타입들)에서 이름이 같은 멤버를 찾습니다.
+func (idx *callIndex) inTypes(name string, types []string) []int {
+	visited := make(map[string]bool)
+	for len(types) > 0 {
+		var found []int
+		var next []string
+		for _, owner := range types {
+			if visited[owner] {
+				continue
+			}
+			visited[owner] = true
+			for _, i := range idx.byOwner[owner] {
+				if idx.nodes[i].Name == name {
+					found = append(found, i)
+				}
+			}
+			next = append(next, idx.baseTypes(owner)...)
+		}
+		if len(found) > 0 {
+			return found
+		}
+		types = next
+	}
+	return nil
+}
+
+// inFiles는 파일과 의존 파일들에서 타입에 속하지 않은 같은 이름의 함수를 찾습니다.
+func (idx *callIndex) inFiles(name, file string, imports []string) []int {
+	var found []int
+	for _, f := range append([]string{file}, imports...) {
+		for _, i := range idx.byFile[f] {
+			if idx.nodes[i].Name == name {
+				found = append(found, i)
+			}
+		}
+		if file == f && len(found) > 0 {
+			return found
+		}
+	}
+	return found
+}
+
+// constructors는 타입들의 생성자 멤버를 찾습니다.
+func (idx *callIndex) constructors(types []string) []int {
+	var found []int
+	for _, owner := range types {
+		for _, i := range idx.byOwner[owner] {
+			name := idx.nodes[i].Name
+			if name == shortTypeName(owner) || contains(constructorNames, name) || idx.nodes[i].Type == "constructor" {
+				found = append(found, i)
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,48 @@
+package project
+
+import (
+	"SkelChunker/src/model"
+	"path/filepath"
+)
+
+// DependenciesFileName은 파일 간 의존 관계를 저장하는 파일 이름입니다.
+const DependenciesFileName = "dependencies.json"
+
+// FileDependencies는 파일 하나가 선언한 네임스페이스와 의존하는 프로젝트 파일들입니다.
+type FileDependencies struct {
+	File       string   `json:"file"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	Imports    []string `json:"imports,omitempty"` // 의존 구문이 가리키는 프로젝트 안의 파일 (resolved)
+}
+
+// DependencyGraph는 프로젝트 전체의 파일 간 의존 관계입니다.
+type DependencyGraph struct {
+	Files []FileDependencies `json:"files"`
+}
+
+// BuildDependencyGraph는 ResolveImports로 연결된 의존 구문으로 파일 간 의존 관계를 만듭니다.
+func BuildDependencyGraph(results []*model.AnalysisResult) *DependencyGraph {
+	graph := &DependencyGraph{}
+	for _, result := range sortedResults(results) {
+		dependencies := FileDependencies{File: FilePath(result)}
+		for _, node := range result.Skeleton {
+			if node.Namespace != "" {
+				dependencies.Namespaces = appendUnique(dependencies.Namespaces, node.Namespace)
+			}
+		}
+		for _, imp := range result.Imports {
+			dependencies.Imports = appendUnique(dependencies.Imports, imp.Resolved...)
+		}
+		graph.Files = append(graph.Files, dependencies)
+	}
+	return graph
+}
+
+// PackageOf는 파일이 속한 패키지 이름을 반환합니다.
+// byNamespace이면 첫 번째 네임스페이스를, 네임스페이스가 없거나 byNamespace가 아니면 폴더 경로를 사용합니다.
+func (f *FileDependencies) PackageOf(byNamespace bool) string {
+	if byNamespace && len(f.Namespaces) > 0 {
+		return f.Namespaces[0]
+	}
+	return filepath.ToSlash(filepath.Dir(f.File))
+}
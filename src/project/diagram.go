@@ -0,0 +1,392 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 다이어그램 종류
+const (
+	DiagramClasses  = "classes"  // 클래스 다이어그램 (상속/구현)
+	DiagramPackages = "packages" // 패키지 의존 다이어그램
+	DiagramCalls    = "calls"    // 호출 그래프
+)
+
+// 다이어그램 간선 종류
+const (
+	EdgeExtends    = "extends"
+	EdgeImplements = "implements"
+	EdgeDepends    = "depends"
+	EdgeCalls      = "calls"
+)
+
+// DiagramScope는 다이어그램에 포함할 범위입니다. 비어 있는 조건은 적용하지 않습니다.
+type DiagramScope struct {
+	Folder    string // 이 폴더 아래 파일에 선언된 요소만
+	Namespace string // 전체 이름이 이 네임스페이스로 시작하는 요소만
+	Root      string // 이 심볼(타입, 메서드, 패키지)에서 닿는 요소만
+	Depth     int    // 패키지/호출 다이어그램에서 Root부터 따라갈 최대 깊이 (0이면 제한 없음)
+}
+
+// DiagramNode는 다이어그램의 노드 하나입니다.
+type DiagramNode struct {
+	ID       string
+	Label    string
+	Members  []string // 클래스 다이어그램의 멤버 표시
+	External bool
+}
+
+// DiagramEdge는 다이어그램의 간선 하나입니다.
+type DiagramEdge struct {
+	From  string
+	To    string
+	Kind  string
+	Label string
+}
+
+// Diagram은 DOT 또는 Mermaid로 출력할 수 있는 다이어그램입니다.
+type Diagram struct {
+	Type  string
+	Nodes []DiagramNode
+	Edges []DiagramEdge
+}
+
+// ClassDiagram은 타입 계층과 심볼 색인으로 범위 안의 타입과 상속/구현 관계 다이어그램을 만듭니다.
+// Root를 지정하면 그 타입의 상위 타입과 하위 타입을 포함합니다.
+func ClassDiagram(hierarchy *TypeHierarchy, symbols *SymbolIndex, scope DiagramScope) *Diagram {
+	included := make(map[string]bool)
+	if scope.Root != "" {
+		roots := hierarchy.Find(scope.Root)
+		related := append(append(roots, hierarchy.Ancestors(scope.Root)...), hierarchy.Descendants(scope.Root)...)
+		for _, node := range related {
+			included[node.Name] = true
+		}
+	}
+
+	byName := make(map[string]TypeHierarchyNode)
+	for _, node := range hierarchy.Types {
+		byName[node.Name] = node
+		if node.External || (scope.Root != "" && !included[node.Name]) {
+			continue
+		}
+		included[node.Name] = scope.matchesNamespace(node.Name) && scope.matchesAnyFile(node.Files)
+	}
+
+	members := make(map[string][]string)
+	if symbols != nil {
+		for _, symbol := range symbols.Symbols {
+			if symbol.Container == "" || !included[symbol.Container] {
+				continue
+			}
+			member := symbol.Name
+			if !typeNodeTypes[symbol.Type] && symbol.Type != "field" && symbol.Type != "property" {
+				member += "()"
+			}
+			members[symbol.Container] = appendUnique(members[symbol.Container], member)
+		}
+	}
+
+	diagram := &Diagram{Type: DiagramClasses}
+	external := make(map[string]bool)
+	for _, node := range hierarchy.Types {
+		if !included[node.Name] || node.External {
+			continue
+		}
+		diagram.Nodes = append(diagram.Nodes, DiagramNode{ID: node.Name, Label: node.Name, Members: members[node.Name]})
+		for _, edge := range []struct {
+			kind  string
+			bases []string
+		}{{EdgeExtends, node.Extends}, {EdgeImplements, node.Implements}} {
+			for _, base := range edge.bases {
+				if byName[base].External {
+					external[base] = true
+				} else if !included[base] {
+					continue
+				}
+				diagram.Edges = append(diagram.Edges, DiagramEdge{From: node.Name, To: base, Kind: edge.kind})
+			}
+		}
+	}
+	for _, name := range sortedKeys(external) {
+		diagram.Nodes = append(diagram.Nodes, DiagramNode{ID: name, Label: name, External: true})
+	}
+	return diagram
+}
+
+// PackageDiagram은 파일 간 의존 관계를 패키지(폴더 또는 네임스페이스) 단위로 묶은 다이어그램을 만듭니다.
+// 범위 밖의 패키지도 범위 안의 패키지가 의존하면 노드로 포함합니다.
+func PackageDiagram(dependencies *DependencyGraph, byNamespace bool, scope DiagramScope) *Diagram {
+	packages := make(map[string]string) // 파일 -> 패키지
+	inScope := make(map[string]bool)    // 범위 안의 패키지
+	for i := range dependencies.Files {
+		file := &dependencies.Files[i]
+		name := file.PackageOf(byNamespace)
+		packages[file.File] = name
+		if scope.matchesFile(file.File) && scope.matchesNamespace(name) {
+			inScope[name] = true
+		}
+	}
+
+	edges := make(map[string]map[string]int)
+	for _, file := range dependencies.Files {
+		from := packages[file.File]
+		for _, imported := range file.Imports {
+			to, exists := packages[imported]
+			if !exists || to == from {
+				continue
+			}
+			if edges[from] == nil {
+				edges[from] = make(map[string]int)
+			}
+			edges[from][to]++
+		}
+	}
+
+	if scope.Root != "" {
+		inScope = reachable([]string{scope.Root}, scope.Depth, func(name string) []string {
+			return sortedKeys(edges[name])
+		})
+	}
+
+	diagram := &Diagram{Type: DiagramPackages}
+	nodes := make(map[string]bool)
+	for _, from := range sortedKeys(edges) {
+		if !inScope[from] {
+			continue
+		}
+		for _, to := range sortedKeys(edges[from]) {
+			if scope.Root != "" && !inScope[to] {
+				continue
+			}
+			nodes[from], nodes[to] = true, true
+			diagram.Edges = append(diagram.Edges, DiagramEdge{From: from, To: to, Kind: EdgeDepends, Label: fmt.Sprint(edges[from][to])})
+		}
+	}
+	for name := range inScope {
+		nodes[name] = true
+	}
+	for _, name := range sortedKeys(nodes) {
+		diagram.Nodes = append(diagram.Nodes, DiagramNode{ID: name, Label: name})
+	}
+	return diagram
+}
+
+// CallDiagram은 호출 그래프에서 범위 안의 메서드/함수와 호출 관계 다이어그램을 만듭니다.
+// Root를 지정하면 그 메서드에서 호출로 닿는 메서드들(Depth 단계까지)을 포함합니다.
+func CallDiagram(graph *CallGraph, scope DiagramScope) *Diagram {
+	byID := make(map[string]*CallGraphNode, len(graph.Nodes))
+	for i := range graph.Nodes {
+		byID[graph.Nodes[i].ID] = &graph.Nodes[i]
+	}
+
+	included := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		included[node.ID] = scope.matchesFile(node.File) && scope.matchesNamespace(node.qualifiedName())
+	}
+	if scope.Root != "" {
+		var roots []string
+		for _, node := range graph.Nodes {
+			name := node.qualifiedName()
+			if node.ID == scope.Root || name == scope.Root || strings.HasSuffix(name, "."+scope.Root) {
+				roots = append(roots, node.ID)
+			}
+		}
+		reached := reachable(roots, scope.Depth, func(id string) []string {
+			if node := byID[id]; node != nil {
+				return node.Callees
+			}
+			return nil
+		})
+		for id := range included {
+			included[id] = included[id] && reached[id]
+		}
+	}
+
+	diagram := &Diagram{Type: DiagramCalls}
+	for _, node := range graph.Nodes {
+		if !included[node.ID] {
+			continue
+		}
+		diagram.Nodes = append(diagram.Nodes, DiagramNode{ID: node.ID, Label: node.qualifiedName()})
+		for _, callee := range node.Callees {
+			if included[callee] {
+				diagram.Edges = append(diagram.Edges, DiagramEdge{From: node.ID, To: callee, Kind: EdgeCalls})
+			}
+		}
+	}
+	return diagram
+}
+
+// reachable은 starts에서 next를 따라 depth 단계(0이면 제한 없음)까지 닿는 이름들을 반환합니다.
+func reachable(starts []string, depth int, next func(name string) []string) map[string]bool {
+	visited := make(map[string]bool)
+	for _, start := range starts {
+		visited[start] = true
+	}
+	level := starts
+	for step := 0; len(level) > 0 && (depth <= 0 || step < depth); step++ {
+		var following []string
+		for _, name := range level {
+			for _, related := range next(name) {
+				if !visited[related] {
+					visited[related] = true
+					following = append(following, related)
+				}
+			}
+		}
+		level = following
+	}
+	return visited
+}
+
+// matchesFile은 파일이 범위의 폴더 아래에 있는지 확인합니다.
+func (s DiagramScope) matchesFile(file string) bool {
+	if s.Folder == "" {
+		return true
+	}
+	folder := filepath.ToSlash(filepath.Clean(s.Folder))
+	file = filepath.ToSlash(filepath.Clean(file))
+	return folder == "." || file == folder || strings.HasPrefix(file, folder+"/")
+}
+
+// matchesAnyFile은 파일 중 하나라도 범위의 폴더 아래에 있는지 확인합니다.
+func (s DiagramScope) matchesAnyFile(files []string) bool {
+	for _, file := range files {
+		if s.matchesFile(file) {
+			return true
+		}
+	}
+	return len(files) == 0 && s.Folder == ""
+}
+
+// matchesNamespace는 이름이 범위의 네임스페이스에 속하는지 확인합니다.
+func (s DiagramScope) matchesNamespace(name string) bool {
+	return s.Namespace == "" || name == s.Namespace || strings.HasPrefix(name, s.Namespace+".")
+}
+
+// DOT은 다이어그램을 Graphviz DOT 형식으로 반환합니다.
+func (d *Diagram) DOT() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %q {\n", d.Type)
+	switch d.Type {
+	case DiagramClasses:
+		sb.WriteString("  rankdir=BT;\n  node [shape=record, fontname=\"Helvetica\"];\n")
+	default:
+		sb.WriteString("  rankdir=LR;\n  node [shape=box, fontname=\"Helvetica\"];\n")
+	}
+
+	for _, node := range d.Nodes {
+		switch {
+		case d.Type == DiagramClasses && !node.External:
+			label := dotRecordEscape(node.Label)
+			if len(node.Members) > 0 {
+				var members []string
+				for _, member := range node.Members {
+					members = append(members, dotRecordEscape(member)+"\\l")
+				}
+				label += "|" + strings.Join(members, "")
+			}
+			fmt.Fprintf(&sb, "  %q [label=\"{%s}\"];\n", node.ID, label)
+		case node.External:
+			fmt.Fprintf(&sb, "  %q [label=%q, style=dashed];\n", node.ID, node.Label)
+		default:
+			fmt.Fprintf(&sb, "  %q [label=%q];\n", node.ID, node.Label)
+		}
+	}
+
+	for _, edge := range d.Edges {
+		var attributes []string
+		switch edge.Kind {
+		case EdgeExtends:
+			attributes = append(attributes, "arrowhead=empty")
+		case EdgeImplements:
+			attributes = append(attributes, "arrowhead=empty", "style=dashed")
+		}
+		if edge.Label != "" {
+			attributes = append(attributes, fmt.Sprintf("label=%q", edge.Label))
+		}
+		fmt.Fprintf(&sb, "  %q -> %q", edge.From, edge.To)
+		if len(attributes) > 0 {
+			fmt.Fprintf(&sb, " [%s]", strings.Join(attributes, ", "))
+		}
+		sb.WriteString(";\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid는 다이어그램을 Mermaid 형식으로 반환합니다. 클래스 다이어그램은 classDiagram, 그 밖에는 flowchart를 사용합니다.
+func (d *Diagram) Mermaid() string {
+	ids := make(map[string]string)
+	for i, node := range d.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d_%s", i, mermaidIDPattern.ReplaceAllString(node.Label, "_"))
+	}
+
+	var sb strings.Builder
+	if d.Type == DiagramClasses {
+		sb.WriteString("classDiagram\n")
+		for _, node := range d.Nodes {
+			fmt.Fprintf(&sb, "  class %s[\"%s\"]", ids[node.ID], mermaidEscape(node.Label))
+			if node.External {
+				fmt.Fprintf(&sb, "\n  <<external>> %s\n", ids[node.ID])
+				continue
+			}
+			if len(node.Members) == 0 {
+				sb.WriteString("\n")
+				continue
+			}
+			sb.WriteString(" {\n")
+			for _, member := range node.Members {
+				fmt.Fprintf(&sb, "    %s\n", strings.NewReplacer("<", "~", ">", "~").Replace(member))
+			}
+			sb.WriteString("  }\n")
+		}
+		for _, edge := range d.Edges {
+			arrow := "<|--"
+			if edge.Kind == EdgeImplements {
+				arrow = "<|.."
+			}
+			fmt.Fprintf(&sb, "  %s %s %s\n", ids[edge.To], arrow, ids[edge.From])
+		}
+		return sb.String()
+	}
+
+	sb.WriteString("flowchart LR\n")
+	for _, node := range d.Nodes {
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[node.ID], mermaidEscape(node.Label))
+	}
+	for _, edge := range d.Edges {
+		if edge.Label != "" {
+			fmt.Fprintf(&sb, "  %s -->|%s| %s\n", ids[edge.From], mermaidEscape(edge.Label), ids[edge.To])
+		} else {
+			fmt.Fprintf(&sb, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+		}
+	}
+	return sb.String()
+}
+
+// mermaidIDPattern은 Mermaid 노드 ID에 쓸 수 없는 문자입니다.
+var mermaidIDPattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// mermaidEscape는 Mermaid 따옴표 레이블에 쓸 수 없는 문자를 엔티티로 바꿉니다.
+func mermaidEscape(text string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(text)
+}
+
+// dotRecordEscape는 DOT record 레이블의 특수 문자를 이스케이프합니다.
+func dotRecordEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`).Replace(text)
+}
+
+// sortedKeys는 맵의 키를 정렬해 반환합니다.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,159 @@
+package project
+
+import (
+	"SkelChunker/src/model"
+	"sort"
+	"strings"
+)
+
+// HierarchyFileName은 프로젝트 타입 계층을 저장하는 파일 이름입니다.
+const HierarchyFileName = "hierarchy.json"
+
+// TypeHierarchyNode는 타입 계층의 타입 하나와 직접 연결된 상위/하위 타입입니다.
+// 프로젝트 밖의 타입(IDisposable 등)도 상속 목록에 쓰이면 external 노드로 포함합니다.
+type TypeHierarchyNode struct {
+	Name       string   `json:"name"` // 타입 전체 이름 (외부 타입은 제네릭 인자를 뺀 이름)
+	Kind       string   `json:"kind,omitempty"`
+	Files      []string `json:"files,omitempty"`
+	External   bool     `json:"external,omitempty"`
+	Extends    []string `json:"extends,omitempty"`
+	Implements []string `json:"implements,omitempty"`
+	Derived    []string `json:"derived,omitempty"` // 이 타입을 직접 상속하거나 구현한 타입
+}
+
+// TypeHierarchy는 프로젝트 전체의 상속/구현 관계입니다.
+type TypeHierarchy struct {
+	Types []TypeHierarchyNode `json:"types"`
+}
+
+// BuildTypeHierarchy는 모든 분석 결과의 타입 선언과 상속 목록으로 타입 계층을 만듭니다.
+// 상속 목록의 이름은 프로젝트 안의 타입 전체 이름으로 바꾸며, partial 선언은 하나의 타입으로 합칩니다.
+func BuildTypeHierarchy(results []*model.AnalysisResult) *TypeHierarchy {
+	index := newTypeIndex()
+	nodes := make(map[string]*TypeHierarchyNode)
+	var declared []model.SkeletonNode
+
+	for _, result := range sortedResults(results) {
+		for _, node := range result.Skeleton {
+			if !typeNodeTypes[node.Type] && len(node.Members) == 0 {
+				continue
+			}
+			name := index.addType(node)
+			if nodes[name] == nil {
+				nodes[name] = &TypeHierarchyNode{Name: name, Kind: node.Kind}
+			}
+			nodes[name].Files = appendUnique(nodes[name].Files, FilePath(result))
+			declared = append(declared, node)
+		}
+	}
+
+	for _, node := range declared {
+		name := node.QualifiedName()
+		nodes[name].Extends = appendUnique(nodes[name].Extends, resolveBaseTypes(index, nodes, node.Extends, name)...)
+		nodes[name].Implements = appendUnique(nodes[name].Implements, resolveBaseTypes(index, nodes, node.Implements, name)...)
+	}
+
+	for _, node := range nodes {
+		for _, base := range append(append([]string{}, node.Extends...), node.Implements...) {
+			nodes[base].Derived = appendUnique(nodes[base].Derived, node.Name)
+		}
+	}
+
+	hierarchy := &TypeHierarchy{}
+	for _, node := range nodes {
+		sort.Strings(node.Derived)
+		hierarchy.Types = append(hierarchy.Types, *node)
+	}
+	sort.Slice(hierarchy.Types, func(i, j int) bool {
+		return hierarchy.Types[i].Name < hierarchy.Types[j].Name
+	})
+	return hierarchy
+}
+
+// resolveBaseTypes는 상속 목록의 이름을 타입 전체 이름으로 바꿉니다.
+// 프로젝트에서 찾지 못한 이름은 external 노드를 만들어 연결합니다.
+func resolveBaseTypes(index *typeIndex, nodes map[string]*TypeHierarchyNode, bases []string, context string) []string {
+	var names []string
+	for _, base := range bases {
+		found := index.findTypes(base, context)
+		if len(found) == 0 {
+			name := baseTypeName(base)
+			if nodes[name] == nil {
+				nodes[name] = &TypeHierarchyNode{Name: name, External: true}
+			}
+			found = []string{name}
+		}
+		names = appendUnique(names, found...)
+	}
+	return names
+}
+
+// Find는 이름에 맞는 타입들을 반환합니다.
+// 이름은 짧은 이름(OrderService), 전체 이름 또는 전체 이름의 끝부분(Orders.OrderService)일 수 있습니다.
+func (h *TypeHierarchy) Find(name string) []TypeHierarchyNode {
+	name = baseTypeName(name)
+	var found []TypeHierarchyNode
+	for _, node := range h.Types {
+		if node.Name == name || strings.HasSuffix(node.Name, "."+name) {
+			found = append(found, node)
+		}
+	}
+	return found
+}
+
+// Ancestors는 타입이 직접 또는 간접으로 상속하거나 구현한 모든 타입을 가까운 순서로 반환합니다.
+func (h *TypeHierarchy) Ancestors(name string) []TypeHierarchyNode {
+	return h.walk(name, func(node *TypeHierarchyNode) []string {
+		return append(append([]string{}, node.Extends...), node.Implements...)
+	})
+}
+
+// Descendants는 타입을 직접 또는 간접으로 상속하거나 구현한 모든 타입을 가까운 순서로 반환합니다.
+func (h *TypeHierarchy) Descendants(name string) []TypeHierarchyNode {
+	return h.walk(name, func(node *TypeHierarchyNode) []string {
+		return node.Derived
+	})
+}
+
+// Implementers는 하위 타입 중 인터페이스가 아닌 타입(구현체)을 반환합니다.
+func (h *TypeHierarchy) Implementers(name string) []TypeHierarchyNode {
+	var implementers []TypeHierarchyNode
+	for _, node := range h.Descendants(name) {
+		if node.Kind != "interface" {
+			implementers = append(implementers, node)
+		}
+	}
+	return implementers
+}
+
+// walk는 name에 맞는 타입들(Find)에서 시작해 next가 반환하는 타입들을 너비 우선으로 방문합니다. 시작 타입은 제외합니다.
+func (h *TypeHierarchy) walk(name string, next func(node *TypeHierarchyNode) []string) []TypeHierarchyNode {
+	byName := make(map[string]*TypeHierarchyNode, len(h.Types))
+	for i := range h.Types {
+		byName[h.Types[i].Name] = &h.Types[i]
+	}
+
+	visited := make(map[string]bool)
+	var queue []string
+	for _, node := range h.Find(name) {
+		visited[node.Name] = true
+		queue = append(queue, node.Name)
+	}
+	var found []TypeHierarchyNode
+	for len(queue) > 0 {
+		node := byName[queue[0]]
+		queue = queue[1:]
+		if node == nil {
+			continue
+		}
+		for _, related := range next(node) {
+			if visited[related] || byName[related] == nil {
+				continue
+			}
+			visited[related] = true
+			found = append(found, *byName[related])
+			queue = append(queue, related)
+		}
+	}
+	return found
+}
@@ -0,0 +1,113 @@
+package project
+
+import (
+	"SkelChunker/src/model"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// moduleExtensions는 확장자 없이 가져온 모듈 경로에 붙여 볼 확장자들입니다.
+var moduleExtensions = []string{".js", ".mjs", ".cjs", ".jsx", ".ts", ".tsx"}
+
+// importIndex는 의존 구문을 파일로 연결하기 위한 프로젝트 색인입니다.
+type importIndex struct {
+	files      map[string]bool     // 슬래시 형식의 파일 경로
+	namespaces map[string][]string // 네임스페이스 -> 선언 파일들
+	types      map[string][]string // 타입 전체 이름 -> 선언 파일들
+}
+
+// ResolveImports는 각 파일의 의존 구문이 가리키는 프로젝트 안의 파일을 찾아 Resolved에 기록합니다.
+// 상대 경로 모듈과 #include는 파일 경로로, using과 그 밖의 이름은 스켈레톤의 네임스페이스와 타입 이름으로 찾습니다.
+func ResolveImports(results []*model.AnalysisResult) {
+	index := importIndex{
+		files:      make(map[string]bool),
+		namespaces: make(map[string][]string),
+		types:      make(map[string][]string),
+	}
+	sorted := sortedResults(results)
+	for _, result := range sorted {
+		file := FilePath(result)
+		index.files[filepath.ToSlash(file)] = true
+		for _, node := range result.Skeleton {
+			if node.Namespace != "" {
+				index.namespaces[node.Namespace] = appendUnique(index.namespaces[node.Namespace], file)
+			}
+			name := node.QualifiedName()
+			index.types[name] = appendUnique(index.types[name], file)
+		}
+	}
+
+	for _, result := range sorted {
+		file := FilePath(result)
+		for i := range result.Imports {
+			imp := &result.Imports[i]
+			imp.Resolved = nil
+			for _, resolved := range index.resolve(file, imp) {
+				if resolved != file {
+					imp.Resolved = append(imp.Resolved, resolved)
+				}
+			}
+		}
+	}
+}
+
+// resolve는 의존 구문 하나가 가리키는 파일들을 찾습니다.
+func (idx *importIndex) resolve(file string, imp *model.Import) []string {
+	target := imp.Path
+	if target == "" {
+		return nil
+	}
+
+	// 상대 경로 모듈 (./util, ../lib/a.js) 또는 #include "dir/a.h"
+	dir := path.Dir(filepath.ToSlash(file))
+	if strings.HasPrefix(target, ".") || imp.Kind == "include" {
+		if found := idx.resolveFile(path.Join(dir, target)); found != "" {
+			return []string{found}
+		}
+	}
+	if imp.Kind == "include" {
+		return idx.resolveSuffix(target)
+	}
+
+	// 타입 이름 (using static, using Alias = Type, Java import)
+	if files, exists := idx.types[target]; exists {
+		return files
+	}
+	// 네임스페이스 (using System.Text)
+	if files, exists := idx.namespaces[target]; exists {
+		return files
+	}
+	return nil
+}
+
+// resolveFile은 모듈 경로에 확장자나 index 파일을 붙여 프로젝트 파일을 찾습니다.
+func (idx *importIndex) resolveFile(modulePath string) string {
+	candidates := []string{modulePath}
+	for _, ext := range moduleExtensions {
+		candidates = append(candidates, modulePath+ext)
+	}
+	for _, ext := range moduleExtensions {
+		candidates = append(candidates, path.Join(modulePath, "index"+ext))
+	}
+	for _, candidate := range candidates {
+		if idx.files[candidate] {
+			return filepath.FromSlash(candidate)
+		}
+	}
+	return ""
+}
+
+// resolveSuffix는 include 경로로 끝나는 프로젝트 파일들을 찾습니다. (include 검색 경로를 알 수 없는 경우)
+func (idx *importIndex) resolveSuffix(includePath string) []string {
+	suffix := "/" + strings.TrimPrefix(path.Clean(includePath), "/")
+	var found []string
+	for file := range idx.files {
+		if strings.HasSuffix("/"+file, suffix) {
+			found = append(found, filepath.FromSlash(file))
+		}
+	}
+	sort.Strings(found)
+	return found
+}
@@ -0,0 +1,77 @@
+package project
+
+import (
+	"sort"
+	"time"
+)
+
+// ManifestFileName은 실행마다 분석한 모든 파일을 요약하는 파일 이름입니다.
+const ManifestFileName = "manifest.json"
+
+// 매니페스트의 파일 상태
+const (
+	ManifestAnalyzed  = "analyzed"  // 새로 분석함
+	ManifestUnchanged = "unchanged" // 파일이 변경되지 않아 기존 결과를 재사용함
+	ManifestFailed    = "failed"    // 분석 또는 저장에 실패함
+)
+
+// ManifestFile은 매니페스트의 파일 하나입니다.
+type ManifestFile struct {
+	File           string `json:"file"`             // 원본 파일 (실행 폴더 기준 상대 경로)
+	Output         string `json:"output,omitempty"` // 결과(.SkelChunker) 파일
+	MD5            string `json:"md5,omitempty"`
+	Language       string `json:"language,omitempty"`
+	Parser         string `json:"parser,omitempty"`
+	Chunks         int    `json:"chunks"`
+	EmbeddingModel string `json:"embeddingModel,omitempty"` // 임베딩이 있는 경우 임베딩 모델
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// ManifestTotals는 매니페스트의 상태별 파일 수와 전체 청크 수입니다.
+type ManifestTotals struct {
+	Files     int `json:"files"`
+	Analyzed  int `json:"analyzed"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+	Chunks    int `json:"chunks"`
+}
+
+// Manifest는 한 번의 실행에서 분석한 모든 파일의 목록과 합계입니다.
+// 결과를 읽는 쪽은 .SkelChunker 파일을 찾아다니지 않고 이 파일 하나로 전체 결과를 찾을 수 있습니다.
+type Manifest struct {
+	GeneratedAt    time.Time      `json:"generatedAt"`
+	OutputRoot     string         `json:"outputRoot"`
+	EmbeddingModel string         `json:"embeddingModel,omitempty"`
+	Commit         string         `json:"commit,omitempty"` // 색인한 git 커밋 (HEAD), 다음 증분 색인의 --since로 사용
+	Since          string         `json:"since,omitempty"`  // 증분 색인이면 변경을 비교한 커밋
+	Totals         ManifestTotals `json:"totals"`
+	Files          []ManifestFile `json:"files"`
+}
+
+// NewManifest는 파일 목록을 경로 순서로 정렬하고 합계를 계산한 매니페스트를 만듭니다.
+func NewManifest(outputRoot string, embeddingModel string, files []ManifestFile) *Manifest {
+	manifest := &Manifest{
+		GeneratedAt:    time.Now().UTC(),
+		OutputRoot:     outputRoot,
+		EmbeddingModel: embeddingModel,
+		Files:          append([]ManifestFile{}, files...),
+	}
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].File < manifest.Files[j].File
+	})
+
+	for _, file := range manifest.Files {
+		manifest.Totals.Files++
+		manifest.Totals.Chunks += file.Chunks
+		switch file.Status {
+		case ManifestAnalyzed:
+			manifest.Totals.Analyzed++
+		case ManifestUnchanged:
+			manifest.Totals.Unchanged++
+		case ManifestFailed:
+			manifest.Totals.Failed++
+		}
+	}
+	return manifest
+}
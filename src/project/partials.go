@@ -0,0 +1,125 @@
+package project
+
+import (
+	"SkelChunker/src/model"
+	"sort"
+)
+
+// PartialsFileName은 병합된 partial 타입 목록을 저장하는 파일 이름입니다.
+const PartialsFileName = "partials.json"
+
+// PartialDeclaration은 partial 타입의 개별 선언 위치를 나타내는 구조체입니다.
+type PartialDeclaration struct {
+	File  string       `json:"file"`
+	Range *model.Range `json:"range,omitempty"`
+}
+
+// PartialType은 여러 선언으로 나뉜 partial 타입을 하나의 논리적 타입으로 묶은 구조체입니다.
+// Skeleton의 멤버에는 각 멤버가 선언된 파일(file)이 기록됩니다.
+type PartialType struct {
+	QualifiedName string               `json:"qualifiedName"`
+	Files         []string             `json:"files"`
+	Declarations  []PartialDeclaration `json:"declarations"`
+	Skeleton      model.SkeletonNode   `json:"skeleton"`
+}
+
+// MergePartialTypes는 모든 분석 결과에서 partial로 선언된 타입을 찾아
+// 같은 전체 이름(네임스페이스 포함)끼리 병합합니다. 선언이 하나뿐인 타입은 제외합니다.
+func MergePartialTypes(results []*model.AnalysisResult) []PartialType {
+	merged := make(map[string]*PartialType)
+	var names []string
+
+	for _, result := range sortedResults(results) {
+		file := FilePath(result)
+		for _, node := range result.Skeleton {
+			if node.Type != "class" || !contains(node.Modifiers, "partial") {
+				continue
+			}
+
+			name := node.QualifiedName()
+			partial, exists := merged[name]
+			if !exists {
+				partial = &PartialType{QualifiedName: name}
+				partial.Skeleton = node
+				partial.Skeleton.Members = nil
+				partial.Skeleton.Range = nil
+				merged[name] = partial
+				names = append(names, name)
+			} else {
+				mergeTypeNode(&partial.Skeleton, node)
+			}
+
+			partial.Declarations = append(partial.Declarations, PartialDeclaration{File: file, Range: node.Range})
+			if len(partial.Files) == 0 || partial.Files[len(partial.Files)-1] != file {
+				partial.Files = append(partial.Files, file)
+			}
+			for _, member := range node.Members {
+				member.File = file
+				partial.Skeleton.Members = append(partial.Skeleton.Members, member)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	var partials []PartialType
+	for _, name := range names {
+		if len(merged[name].Declarations) > 1 {
+			partials = append(partials, *merged[name])
+		}
+	}
+	return partials
+}
+
+// mergeTypeNode는 다른 선언의 상속 목록, 제어자, 특성 등을 병합 대상 노드에 합칩니다.
+func mergeTypeNode(target *model.SkeletonNode, node model.SkeletonNode) {
+	target.Extends = appendUnique(target.Extends, node.Extends...)
+	target.Implements = appendUnique(target.Implements, node.Implements...)
+	target.Modifiers = appendUnique(target.Modifiers, node.Modifiers...)
+	target.Attributes = appendUnique(target.Attributes, node.Attributes...)
+	if target.Doc == nil {
+		target.Doc = node.Doc
+	}
+	if len(target.TypeParameters) == 0 {
+		target.TypeParameters = node.TypeParameters
+	}
+	for _, param := range node.TypeParameters {
+		for i := range target.TypeParameters {
+			if target.TypeParameters[i].Name == param.Name {
+				target.TypeParameters[i].Constraints = appendUnique(target.TypeParameters[i].Constraints, param.Constraints...)
+			}
+		}
+	}
+	// 접근 제한자는 한 선언에만 명시해도 되므로 생략 시 채워지는 기본값은 다른 선언의 값을 덮어쓰지 않음
+	if node.Visibility != "" && node.Visibility != defaultVisibility(node) {
+		target.Visibility = node.Visibility
+	}
+}
+
+// defaultVisibility는 접근 제한자를 생략한 타입에 파서가 채우는 기본값을 반환합니다.
+// 최상위 타입은 internal, 중첩 타입은 private입니다.
+func defaultVisibility(node model.SkeletonNode) string {
+	if node.Parent != "" {
+		return "private"
+	}
+	return "internal"
+}
+
+// contains는 목록에 주어진 값이 있는지 확인합니다.
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// appendUnique는 목록에 없는 값만 순서를 유지하며 추가합니다.
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		if !contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
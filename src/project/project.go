@@ -0,0 +1,59 @@
+package project
+
+import (
+	"SkelChunker/src/model"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// sortedResults는 파일 경로 순으로 정렬된 분석 결과 목록을 반환합니다.
+// 프로젝트 단위 결과가 파일 탐색 순서와 관계없이 항상 같도록 하기 위해 사용합니다.
+func sortedResults(results []*model.AnalysisResult) []*model.AnalysisResult {
+	sorted := make([]*model.AnalysisResult, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			sorted = append(sorted, result)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return FilePath(sorted[i]) < FilePath(sorted[j])
+	})
+	return sorted
+}
+
+// FilePath는 분석 결과의 원본 파일 경로를 반환합니다.
+func FilePath(result *model.AnalysisResult) string {
+	return filepath.Join(result.Path, result.Filename)
+}
+
+// WriteJSON은 프로젝트 단위 결과를 출력 폴더의 JSON 파일로 저장합니다.
+func WriteJSON(outputDir, name string, v interface{}) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create project output folder: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(outputDir, name), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// ReadJSON은 출력 폴더의 프로젝트 단위 결과 JSON 파일을 읽습니다.
+func ReadJSON(outputDir, name string, v interface{}) error {
+	data, err := os.ReadFile(filepath.Join(outputDir, name))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package project
+
+import (
+	"SkelChunker/src/model"
+	"strings"
+)
+
+// SymbolsFileName은 프로젝트 심볼 색인을 저장하는 파일 이름입니다.
+const SymbolsFileName = "symbols.json"
+
+// Symbol은 프로젝트에 선언된 타입, 멤버, 함수 하나입니다.
+type Symbol struct {
+	Name          string       `json:"name"`
+	QualifiedName string       `json:"qualifiedName"`
+	Type          string       `json:"type"`
+	Kind          string       `json:"kind,omitempty"`
+	Container     string       `json:"container,omitempty"` // 멤버가 속한 타입의 전체 이름
+	File          string       `json:"file"`
+	Range         *model.Range `json:"range,omitempty"`
+	Signature     string       `json:"signature,omitempty"`
+}
+
+// SymbolReference는 식별자가 나타난 위치 하나입니다. 라인과 열은 1부터 시작합니다.
+type SymbolReference struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+}
+
+// SymbolIndex는 프로젝트의 심볼 선언과 이름별 식별자 위치입니다.
+// 색인 크기를 줄이기 위해 프로젝트 안에 선언된 이름의 식별자만 기록합니다.
+type SymbolIndex struct {
+	Symbols    []Symbol                     `json:"symbols"`
+	References map[string][]SymbolReference `json:"references"`
+}
+
+// BuildSymbolIndex는 모든 분석 결과의 스켈레톤과 식별자 위치로 심볼 색인을 만듭니다.
+func BuildSymbolIndex(results []*model.AnalysisResult) *SymbolIndex {
+	index := &SymbolIndex{References: make(map[string][]SymbolReference)}
+	sorted := sortedResults(results)
+	declared := make(map[string]bool)
+
+	for _, result := range sorted {
+		file := FilePath(result)
+		for _, node := range result.Skeleton {
+			qualifiedName := node.QualifiedName()
+			index.Symbols = append(index.Symbols, Symbol{
+				Name:          node.Name,
+				QualifiedName: qualifiedName,
+				Type:          node.Type,
+				Kind:          node.Kind,
+				Container:     node.Parent,
+				File:          file,
+				Range:         node.Range,
+				Signature:     node.Signature,
+			})
+			declared[node.Name] = true
+			for _, member := range node.Members {
+				index.Symbols = append(index.Symbols, Symbol{
+					Name:          member.Name,
+					QualifiedName: qualifiedName + "." + member.Name,
+					Type:          member.Type,
+					Container:     qualifiedName,
+					File:          file,
+					Range:         member.Range,
+					Signature:     member.Signature,
+				})
+				declared[member.Name] = true
+			}
+		}
+	}
+
+	for _, result := range sorted {
+		file := FilePath(result)
+		for _, identifier := range result.Identifiers {
+			if !declared[identifier.Name] {
+				continue
+			}
+			index.References[identifier.Name] = append(index.References[identifier.Name], SymbolReference{
+				File:   file,
+				Line:   identifier.Line,
+				Column: identifier.Column,
+				Start:  identifier.Start,
+				End:    identifier.End,
+			})
+		}
+	}
+	return index
+}
+
+// Definitions는 심볼 이름에 맞는 선언들을 반환합니다.
+// 이름은 짧은 이름(Save), 전체 이름(Shop.Repo.Save) 또는 전체 이름의 끝부분(Repo.Save)일 수 있습니다.
+func (idx *SymbolIndex) Definitions(name string) []Symbol {
+	var symbols []Symbol
+	for _, symbol := range idx.Symbols {
+		if symbol.QualifiedName == name || symbol.Name == name || strings.HasSuffix(symbol.QualifiedName, "."+name) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// FindReferences는 심볼 이름(전체 이름이면 마지막 이름)과 같은 식별자가 나타난 위치들을 반환합니다.
+// 타입 정보 없이 이름으로 찾으므로 같은 이름의 다른 심볼 위치도 포함될 수 있습니다.
+func (idx *SymbolIndex) FindReferences(name string) []SymbolReference {
+	return idx.References[shortTypeName(name)]
+}
@@ -0,0 +1,74 @@
+package project
+
+import (
+	"SkelChunker/src/model"
+	"strings"
+)
+
+// typeIndex는 상속 목록과 호출에 쓰인 타입 이름을 프로젝트 안의 타입 전체 이름으로 찾기 위한 색인입니다.
+type typeIndex struct {
+	types map[string][]string // 타입 이름(짧은 이름과 전체 이름) -> 타입 전체 이름들
+	bases map[string][]string // 타입 전체 이름 -> 상속/구현 목록 (선언에 쓰인 그대로)
+}
+
+// newTypeIndex는 빈 타입 색인을 생성합니다.
+func newTypeIndex() *typeIndex {
+	return &typeIndex{
+		types: make(map[string][]string),
+		bases: make(map[string][]string),
+	}
+}
+
+// addType은 타입 선언을 색인에 추가하고 전체 이름을 반환합니다. partial 선언은 같은 타입으로 합쳐집니다.
+func (idx *typeIndex) addType(node model.SkeletonNode) string {
+	name := node.QualifiedName()
+	idx.types[node.Name] = appendUnique(idx.types[node.Name], name)
+	idx.types[name] = appendUnique(idx.types[name], name)
+	idx.bases[name] = appendUnique(idx.bases[name], node.Extends...)
+	idx.bases[name] = appendUnique(idx.bases[name], node.Implements...)
+	return name
+}
+
+// baseTypes는 타입의 상속/구현 목록을 프로젝트 안의 타입 전체 이름으로 바꿔 반환합니다.
+func (idx *typeIndex) baseTypes(owner string) []string {
+	var types []string
+	for _, base := range idx.bases[owner] {
+		types = appendUnique(types, idx.findTypes(base, owner)...)
+	}
+	return types
+}
+
+// findTypes는 타입 이름(제네릭 인자 포함 가능)을 프로젝트 안의 타입 전체 이름으로 찾습니다.
+// 같은 짧은 이름의 타입이 여럿이면 context와 네임스페이스가 같은 쪽을 우선합니다.
+func (idx *typeIndex) findTypes(name, context string) []string {
+	candidates := idx.types[baseTypeName(name)]
+	if len(candidates) <= 1 {
+		return candidates
+	}
+	namespace := context
+	if index := strings.LastIndex(context, "."); index >= 0 {
+		namespace = context[:index]
+	}
+	for _, candidate := range candidates {
+		if namespace != "" && strings.HasPrefix(candidate, namespace+".") {
+			return []string{candidate}
+		}
+	}
+	return candidates
+}
+
+// baseTypeName은 타입 이름에서 제네릭 인자와 배열 표기를 뺀 이름을 반환합니다. (IRepository<T> -> IRepository)
+func baseTypeName(name string) string {
+	if index := strings.IndexAny(name, "<(["); index >= 0 {
+		name = name[:index]
+	}
+	return strings.TrimSpace(name)
+}
+
+// shortTypeName은 전체 이름에서 마지막 이름만 반환합니다.
+func shortTypeName(name string) string {
+	if index := strings.LastIndex(name, "."); index >= 0 {
+		return name[index+1:]
+	}
+	return name
+}
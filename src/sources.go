@@ -0,0 +1,184 @@
+package main
+
+import (
+	"SkelChunker/src/analyzer"
+	"SkelChunker/src/config"
+	"SkelChunker/src/ignore"
+	"SkelChunker/src/parser"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// sourceWalker는 설정된 폴더에서 분석할 파일과, 소스 폴더 안에 남아 있는 결과 파일을 찾습니다.
+// 여러 폴더를 동시에 탐색할 수 있으므로 찾은 목록은 mu로 보호합니다.
+type sourceWalker struct {
+	cfg           *config.Config
+	parserFactory *parser.ParserFactory
+	outputRoot    string                     // 탐색에서 제외할 결과 폴더 (절대 경로, 소스 파일 옆에 저장하면 빈 문자열)
+	matchers      map[string]*ignore.Matcher // 폴더별 무시 파일과 포함/제외 패턴
+
+	mu      sync.Mutex
+	files   []string // 분석 대상 파일
+	outputs []string // 소스 폴더에서 찾은 결과 파일
+}
+
+// newSourceWalker는 설정의 무시 폴더, 무시 파일, 포함/제외 패턴과 파서 판별 규칙으로 파일을 찾는 새로운 sourceWalker를 생성합니다.
+// 무시 파일은 현재 작업 폴더부터 읽으므로, 저장소 최상위의 .gitignore도 하위 분석 폴더에 적용됩니다.
+func newSourceWalker(cfg *config.Config, parserFactory *parser.ParserFactory) (*sourceWalker, error) {
+	walker := &sourceWalker{cfg: cfg, parserFactory: parserFactory, matchers: make(map[string]*ignore.Matcher)}
+	if cfg.OutputRoot != "." {
+		walker.outputRoot, _ = filepath.Abs(cfg.OutputRoot)
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range cfg.Folders {
+		matcher, err := ignore.NewMatcher(folder, ignore.Config{
+			Root:      workDir,
+			FileNames: cfg.IgnoreFiles,
+			Include:   cfg.Include,
+			Exclude:   cfg.Exclude,
+		})
+		if err != nil {
+			return nil, err
+		}
+		walker.matchers[folder] = matcher
+	}
+	return walker, nil
+}
+
+// walk는 폴더 하나를 탐색하여 분석할 파일을 찾은 순서대로 visit에 넘깁니다 (analyzer.WalkFunc).
+func (w *sourceWalker) walk(folder string, visit func(path string)) error {
+	matcher := w.matchers[folder]
+	return filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// 디렉토리인 경우 무시 폴더 체크 (소스 폴더 안에 있는 결과 폴더도 제외)
+		if info.IsDir() {
+			if absPath, _ := filepath.Abs(path); absPath == w.outputRoot {
+				return filepath.SkipDir
+			}
+			if shouldIgnoreFolder(path, w.cfg.IgnoreFolders) || matcher.Ignored(path, true) {
+				fmt.Printf("Skipping ignored folder: %s\n", path)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// 이전 결과 파일은 오래된 결과 정리를 위해 기록
+		if strings.HasSuffix(path, analyzer.ResultExtension) {
+			w.mu.Lock()
+			w.outputs = append(w.outputs, path)
+			w.mu.Unlock()
+			return nil
+		}
+
+		// 무시 파일이나 제외 패턴에 일치하거나 포함 패턴에 일치하지 않는 파일 제외
+		if matcher.Ignored(path, false) || !matcher.Included(path) {
+			return nil
+		}
+
+		// 분석할 파서가 있는 파일인지 확인 (확장자, 파일 이름, shebang 등)
+		if _, err := w.parserFactory.DetectFile(path); err != nil {
+			return nil
+		}
+
+		w.mu.Lock()
+		w.files = append(w.files, path)
+		w.mu.Unlock()
+		visit(path)
+		return nil
+	})
+}
+
+// walkFiles는 폴더를 탐색하는 대신 주어진 파일 중 폴더 안에 있는 분석 대상 파일만 visit에 넘기는 analyzer.WalkFunc를 반환합니다.
+// 변경된 파일만 다시 분석하는 증분 색인에서 사용합니다.
+func (w *sourceWalker) walkFiles(files []string) analyzer.WalkFunc {
+	return func(folder string, visit func(path string)) error {
+		for _, path := range files {
+			if !inFolder(path, folder) || !w.accepts(path, folder) {
+				continue
+			}
+			w.mu.Lock()
+			w.files = append(w.files, path)
+			w.mu.Unlock()
+			visit(path)
+		}
+		return nil
+	}
+}
+
+// accepts는 파일이 walk가 폴더에서 분석 대상으로 찾는 파일인지 확인합니다.
+// 결과 폴더나 무시 폴더 안의 파일, 결과 파일, 무시 파일이나 포함/제외 패턴으로 제외된 파일, 파서가 없는 파일은 제외합니다.
+func (w *sourceWalker) accepts(path string, folder string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || strings.HasSuffix(path, analyzer.ResultExtension) {
+		return false
+	}
+	if absPath, _ := filepath.Abs(path); w.outputRoot != "" && strings.HasPrefix(absPath, w.outputRoot+string(filepath.Separator)) {
+		return false
+	}
+	if shouldIgnoreFolder(filepath.Dir(path), w.cfg.IgnoreFolders) {
+		return false
+	}
+	if matcher := w.matchers[folder]; matcher.Ignored(path, false) || !matcher.Included(path) {
+		return false
+	}
+	_, err = w.parserFactory.DetectFile(path)
+	return err == nil
+}
+
+// inFolder는 파일이 폴더 안에 있는지 확인합니다.
+func inFolder(path string, folder string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return false
+	}
+	relPath, err := filepath.Rel(absFolder, absPath)
+	return err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator))
+}
+
+// indexes는 파일이 설정된 폴더 중 하나에 있는 분석 대상 파일인지 확인합니다.
+func (w *sourceWalker) indexes(path string) bool {
+	for _, folder := range w.cfg.Folders {
+		if inFolder(path, folder) {
+			return w.accepts(path, folder)
+		}
+	}
+	return false
+}
+
+// staleOutputs는 탐색한 결과로 오래된 결과 파일을 찾습니다. 탐색이 끝난 뒤 호출해야 합니다.
+func (w *sourceWalker) staleOutputs(fileAnalyzer *analyzer.Analyzer) ([]analyzer.StaleOutput, error) {
+	outputs := append([]string{}, w.outputs...)
+	if w.outputRoot != "" {
+		rootOutputs, err := analyzer.ListOutputs(w.cfg.OutputRoot)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, rootOutputs...)
+	}
+	return fileAnalyzer.StaleOutputs(outputs, w.files), nil
+}
+
+// describeStaleOutput은 오래된 결과 파일과 정리 사유를 한 줄로 만듭니다.
+func describeStaleOutput(item analyzer.StaleOutput) string {
+	switch item.Reason {
+	case analyzer.StaleRenamed:
+		return fmt.Sprintf("%s (%s renamed to %s)", item.Path, item.Source, item.RenamedTo)
+	case analyzer.StaleLegacy:
+		return fmt.Sprintf("%s (legacy output next to sources)", item.Path)
+	default:
+		return fmt.Sprintf("%s (%s %s)", item.Path, item.Source, item.Reason)
+	}
+}
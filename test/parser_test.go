@@ -2,11 +2,24 @@ package parser_test
 
 import (
 	"fmt"
+	"SkelChunker/src/analyzer"
+	"SkelChunker/src/embeddings"
+	"SkelChunker/src/ignore"
+	"SkelChunker/src/model"
 	"SkelChunker/src/parser"
+	"SkelChunker/src/project"
 	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 func TestCSharpParser(t *testing.T) {
@@ -49,4 +62,1670 @@ func TestCSharpParser(t *testing.T) {
 		fmt.Printf("청크 %d - MD5: %s\n텍스트 (길이 %d):\n%s\n\n", 
 			i, chunk.MD5, len(chunk.Text), chunk.Text)
 	}
-} 
\ No newline at end of file
+} 
+
+func TestCSharpParserUTF8Offsets(t *testing.T) {
+	// BOM과 한글 주석, 이스케이프된 문자열이 포함된 소스
+	source := "\xEF\xBB\xBF// 한글 주석입니다\n" +
+		"public class 샘플\n" +
+		"{\n" +
+		"    // 메서드 설명: \"따옴표\"\n" +
+		"    public void 인사()\n" +
+		"    {\n" +
+		"        var s = \"안녕 \\\"세계\\\" \\\\\";\n" +
+		"    }\n" +
+		"\n" +
+		"    public int Add(int a, int b)\n" +
+		"    {\n" +
+		"        return a + b; /* 합계 */\n" +
+		"    }\n" +
+		"}\n"
+
+	p := parser.NewCSharpParser()
+	nodes, chunks, err := p.Parse(source)
+	if err != nil {
+		t.Fatalf("파싱 중 오류 발생: %v", err)
+	}
+
+	if len(nodes) != 1 || nodes[0].Name != "샘플" {
+		t.Fatalf("클래스 노드가 올바르지 않습니다: %+v", nodes)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("청크 수가 올바르지 않습니다: %d", len(chunks))
+	}
+
+	expectedLines := [][2]int{{4, 8}, {10, 13}}
+	for i, chunk := range chunks {
+		if chunk.Range == nil {
+			t.Fatalf("청크 %d에 범위가 없습니다", i)
+		}
+		if got := source[chunk.Range.Start:chunk.Range.End]; got != chunk.Text {
+			t.Errorf("청크 %d의 오프셋이 텍스트와 일치하지 않습니다: %q", i, got)
+		}
+		if chunk.Range.StartLine != expectedLines[i][0] || chunk.Range.EndLine != expectedLines[i][1] {
+			t.Errorf("청크 %d의 라인 범위가 올바르지 않습니다: %d-%d", i, chunk.Range.StartLine, chunk.Range.EndLine)
+		}
+	}
+}
+
+// rangeSpan은 범위를 "시작라인:열-끝라인:열 @시작-끝" 형식으로 만듭니다.
+// 열은 1부터 시작하는 문자 단위이며, 끝 열은 마지막 문자 다음 위치입니다 (끝 오프셋과 같이 포함하지 않음).
+func rangeSpan(source string, r *model.Range) string {
+	if r == nil {
+		return "<nil>"
+	}
+	column := func(offset int) int {
+		lineStart := strings.LastIndex(source[:offset], "\n") + 1
+		return utf8.RuneCountInString(source[lineStart:offset]) + 1
+	}
+	return fmt.Sprintf("%d:%d-%d:%d @%d-%d", r.StartLine, column(r.Start), r.EndLine, column(r.End), r.Start, r.End)
+}
+
+func TestParserRanges(t *testing.T) {
+	source := "namespace Shop\n" +
+		"{\n" +
+		"    public class Cart\n" +
+		"    {\n" +
+		"        public int Count() { return 0; }\n" +
+		"\n" +
+		"        public decimal Total(\n" +
+		"            decimal tax,\n" +
+		"            decimal discount)\n" +
+		"        {\n" +
+		"            return tax - discount;\n" +
+		"        }\n" +
+		"    }\n" +
+		"}\n"
+	nodes, chunks, err := parser.NewCSharpParser().Parse(source)
+	if err != nil {
+		t.Fatalf("파싱 오류: %v", err)
+	}
+	if len(nodes) != 1 || len(nodes[0].Members) != 2 {
+		t.Fatalf("노드가 올바르지 않습니다: %+v", nodes)
+	}
+
+	// 타입은 선언부터 닫는 중괄호까지, 멤버는 들여쓰기를 뺀 선언 시작부터 (여러 줄에 걸친 시그니처와 본문 포함)
+	cart := nodes[0]
+	expected := []struct {
+		name string
+		r    *model.Range
+		span string
+	}{
+		{"Cart", cart.Range, "3:5-13:6 @21-232"},
+		{"Count", cart.Members[0].Range, "5:9-5:41 @53-85"},
+		{"Total", cart.Members[1].Range, "7:9-12:10 @95-226"},
+	}
+	for _, tt := range expected {
+		if got := rangeSpan(source, tt.r); got != tt.span {
+			t.Errorf("%s 범위가 올바르지 않습니다: %s (기대값 %s)", tt.name, got, tt.span)
+		}
+	}
+	if text := source[cart.Members[1].Range.Start:cart.Members[1].Range.End]; !strings.HasPrefix(text, "public decimal Total(") || !strings.HasSuffix(text, "}") {
+		t.Errorf("여러 줄 멤버의 오프셋이 선언과 맞지 않습니다: %q", text)
+	}
+	for i, chunk := range chunks {
+		if chunk.Range == nil || source[chunk.Range.Start:chunk.Range.End] != chunk.Text {
+			t.Errorf("청크 %d의 범위가 텍스트와 일치하지 않습니다: %+v", i, chunk.Range)
+		}
+	}
+	if len(chunks) != 2 || chunks[1].Range.EndLine != 12 {
+		t.Errorf("멤버 청크의 범위가 올바르지 않습니다: %+v", chunks)
+	}
+	jsSource := "class Cart {\n" +
+		"  total(tax, discount) {\n" +
+		"    const sum = tax\n" +
+		"      - discount;\n" +
+		"    return sum;\n" +
+		"  }\n" +
+		"}\n" +
+		"\n" +
+		"function main() {\n" +
+		"  return new Cart();\n" +
+		"}\n"
+	jsNodes, _, err := parser.NewJavaScriptParser().Parse(jsSource)
+	if err != nil {
+		t.Fatalf("파싱 오류: %v", err)
+	}
+	if len(jsNodes) != 2 || len(jsNodes[0].Members) != 1 {
+		t.Fatalf("JavaScript 노드가 올바르지 않습니다: %+v", jsNodes)
+	}
+	// JavaScript 파서는 줄 단위로 범위를 정하므로 멤버도 들여쓰기를 포함한 줄 시작부터
+	for _, tt := range []struct {
+		name string
+		r    *model.Range
+		span string
+	}{
+		{"Cart", jsNodes[0].Range, "1:1-7:2 @0-97"},
+		{"total", jsNodes[0].Members[0].Range, "2:1-6:4 @13-95"},
+		{"main", jsNodes[1].Range, "9:1-11:2 @99-139"},
+	} {
+		if got := rangeSpan(jsSource, tt.r); got != tt.span {
+			t.Errorf("%s 범위가 올바르지 않습니다: %s (기대값 %s)", tt.name, got, tt.span)
+		}
+	}
+}
+
+func TestCSharpParserSignatures(t *testing.T) {
+	source := `public class Calculator
+{
+    public int Count { get; private set; }
+
+    public static int Add(int a, int b) { return a + b; }
+
+    public static double Add(double a, double b = 1.0) { return a + b; }
+
+    protected virtual async Task<List<int>> LoadAsync<T>(ref T item, params string[] tags) where T : class
+    {
+        return null;
+    }
+}
+`
+	p := parser.NewCSharpParser()
+	nodes, _, err := p.Parse(source)
+	if err != nil {
+		t.Fatalf("파싱 중 오류 발생: %v", err)
+	}
+	if len(nodes) != 1 || len(nodes[0].Members) != 4 {
+		t.Fatalf("멤버가 올바르게 추출되지 않았습니다: %+v", nodes)
+	}
+
+	members := nodes[0].Members
+	if members[1].Signature == members[2].Signature {
+		t.Errorf("오버로드된 메서드의 시그니처가 구분되지 않습니다: %s", members[1].Signature)
+	}
+	if members[2].Signature != "public static double Add(double a, double b = 1.0)" {
+		t.Errorf("시그니처가 올바르지 않습니다: %s", members[2].Signature)
+	}
+	if members[2].Parameters[1].Default != "1.0" {
+		t.Errorf("매개변수 기본값이 올바르지 않습니다: %+v", members[2].Parameters)
+	}
+
+	load := members[3]
+	if load.Name != "LoadAsync" || load.ReturnType != "Task<List<int>>" || load.Visibility != "protected" {
+		t.Errorf("메서드 정보가 올바르지 않습니다: %+v", load)
+	}
+	if strings.Join(load.Modifiers, " ") != "virtual async" {
+		t.Errorf("제어자가 올바르지 않습니다: %v", load.Modifiers)
+	}
+	if len(load.Parameters) != 2 || load.Parameters[0].Type != "ref T" || load.Parameters[1].Name != "tags" {
+		t.Errorf("매개변수가 올바르지 않습니다: %+v", load.Parameters)
+	}
+}
+
+func TestCSharpParserOperators(t *testing.T) {
+	// operator > 의 > 를 제네릭 인자 목록의 끝으로 보면 안 됨
+	source := `public struct Version
+{
+    public static bool operator >(Version a, Version b) => true;
+    public static bool operator <(Version a, Version b) => false;
+    public static bool operator >=(Version a, Version b) { return true; }
+    public static Version operator >>(Version a, int bits) => a;
+    public List<T> Get<T>(int index) => null;
+}
+`
+	p := parser.NewCSharpParser()
+	nodes, _, err := p.Parse(source)
+	if err != nil {
+		t.Fatalf("파싱 중 오류 발생: %v", err)
+	}
+	if len(nodes) != 1 || len(nodes[0].Members) != 5 {
+		t.Fatalf("멤버가 올바르게 추출되지 않았습니다: %+v", nodes)
+	}
+
+	expected := []struct{ name, returnType, signature string }{
+		{"operator>", "bool", "public static bool operator >(Version a, Version b)"},
+		{"operator<", "bool", "public static bool operator <(Version a, Version b)"},
+		{"operator>=", "bool", "public static bool operator >=(Version a, Version b)"},
+		{"operator>>", "Version", "public static Version operator >>(Version a, int bits)"},
+		{"Get", "List<T>", "public List<T> Get<T>(int index)"},
+	}
+	for i, want := range expected {
+		member := nodes[0].Members[i]
+		if member.Name != want.name || member.ReturnType != want.returnType || member.Signature != want.signature {
+			t.Errorf("연산자 오버로드 %d가 올바르지 않습니다: %s, %s, %s", i, member.Name, member.ReturnType, member.Signature)
+		}
+		if len(member.Parameters) != 2 && want.name != "Get" {
+			t.Errorf("%s의 매개변수가 올바르지 않습니다: %+v", member.Name, member.Parameters)
+		}
+	}
+}
+
+func TestCSharpParserDocComments(t *testing.T) {
+	source := `public class Service
+{
+    public void Stop() { } // 종료
+
+    /// <summary>
+    /// 사용자를 <see cref="User"/>로 찾습니다.
+    /// </summary>
+    /// <param name="id">사용자 ID</param>
+    /// <returns>찾은 사용자</returns>
+    [HttpGet("users/{id}"), Authorize]
+    public User Find(int id) { return null; }
+}
+`
+	p := parser.NewCSharpParser()
+	nodes, chunks, err := p.Parse(source)
+	if err != nil {
+		t.Fatalf("파싱 중 오류 발생: %v", err)
+	}
+	if len(nodes) != 1 || len(nodes[0].Members) != 2 || len(chunks) != 2 {
+		t.Fatalf("멤버가 올바르게 추출되지 않았습니다: %+v", nodes)
+	}
+
+	find := nodes[0].Members[1]
+	if find.Doc == nil || find.Doc.Summary != "사용자를 User로 찾습니다." || find.Doc.Returns != "찾은 사용자" {
+		t.Errorf("문서 주석이 올바르지 않습니다: %+v", find.Doc)
+	}
+	if len(find.Doc.Params) != 1 || find.Doc.Params[0].Name != "id" || find.Doc.Params[0].Description != "사용자 ID" {
+		t.Errorf("매개변수 설명이 올바르지 않습니다: %+v", find.Doc.Params)
+	}
+	if strings.Join(find.Attributes, "|") != `HttpGet("users/{id}")|Authorize` {
+		t.Errorf("특성이 올바르지 않습니다: %v", find.Attributes)
+	}
+
+	// 문서 주석과 특성은 청크에 포함되고, 앞 멤버의 줄 끝 주석은 포함되지 않아야 함
+	if !strings.HasPrefix(strings.TrimSpace(chunks[1].Text), "/// <summary>") {
+		t.Errorf("청크가 문서 주석으로 시작하지 않습니다: %q", chunks[1].Text)
+	}
+	if strings.Contains(chunks[1].Text, "종료") {
+		t.Errorf("앞 멤버의 주석이 청크에 포함되었습니다: %q", chunks[1].Text)
+	}
+}
+
+func TestCSharpParserTypeHierarchy(t *testing.T) {
+	source := `public class Repository<T> : RepositoryBase<T>, IRepository<T>, IDisposable where T : class, new()
+{
+    public void Dispose() { }
+}
+public interface IRepository<T> : IReadOnlyRepository<T> { }
+public struct Point : IEquatable<Point> { }
+public record Person(string Name) : Entity(Name);
+public enum Color : byte { Red, Green }
+`
+	p := parser.NewCSharpParser()
+	nodes, _, err := p.Parse(source)
+	if err != nil {
+		t.Fatalf("파싱 중 오류 발생: %v", err)
+	}
+	if len(nodes) != 5 {
+		t.Fatalf("타입 수가 올바르지 않습니다: %d", len(nodes))
+	}
+
+	expected := []struct {
+		kind, extends, implements string
+	}{
+		{"class", "RepositoryBase<T>", "IRepository<T>|IDisposable"},
+		{"interface", "IReadOnlyRepository<T>", ""},
+		{"struct", "", "IEquatable<Point>"},
+		{"record", "Entity", ""},
+		{"enum", "", ""},
+	}
+	for i, e := range expected {
+		node := nodes[i]
+		if node.Kind != e.kind || strings.Join(node.Extends, "|") != e.extends || strings.Join(node.Implements, "|") != e.implements {
+			t.Errorf("%s: kind=%s extends=%v implements=%v", node.Name, node.Kind, node.Extends, node.Implements)
+		}
+	}
+
+	typeParams := nodes[0].TypeParameters
+	if len(typeParams) != 1 || typeParams[0].Name != "T" || strings.Join(typeParams[0].Constraints, ",") != "class,new()" {
+		t.Errorf("제네릭 제약 조건이 올바르지 않습니다: %+v", typeParams)
+	}
+}
+
+func TestMergePartialTypes(t *testing.T) {
+	sources := map[string]string{
+		"Order.cs": "namespace Shop\n" +
+			"{\n" +
+			"    public partial class Order : Entity\n" +
+			"    {\n" +
+			"        public int Id { get; set; }\n" +
+			"    }\n" +
+			"\n" +
+			"    public partial class Single { }\n" +
+			"}\n",
+		"Order.Validation.cs": "namespace Shop\n" +
+			"{\n" +
+			"    partial class Order : IValidatable\n" +
+			"    {\n" +
+			"        public bool Validate() { return Id > 0; }\n" +
+			"    }\n" +
+			"}\n",
+		"Generated/Order.g.cs": "namespace Shop\n" +
+			"{\n" +
+			"    [Serializable]\n" +
+			"    partial class Order : IValidatable, IDisposable\n" +
+			"    {\n" +
+			"        public void Dispose() { }\n" +
+			"        public string Describe() { return \"\"; }\n" +
+			"    }\n" +
+			"}\n",
+	}
+	csharp := parser.NewCSharpParser()
+	var results []*model.AnalysisResult
+	for file, source := range sources {
+		nodes, _, err := csharp.Parse(source)
+		if err != nil {
+			t.Fatalf("%s 파싱 오류: %v", file, err)
+		}
+		results = append(results, &model.AnalysisResult{Path: path.Dir("src/" + file), Filename: path.Base(file), Skeleton: nodes})
+	}
+
+	partials := project.MergePartialTypes(results)
+	if len(partials) != 1 || partials[0].QualifiedName != "Shop.Order" {
+		t.Fatalf("선언이 하나뿐인 타입은 제외하고 Shop.Order만 병합해야 합니다: %+v", partials)
+	}
+	order := partials[0]
+
+	// 파일은 경로 순서로 한 번씩
+	if got := strings.Join(order.Files, ","); got != "src/Generated/Order.g.cs,src/Order.Validation.cs,src/Order.cs" {
+		t.Errorf("파일 목록이 올바르지 않습니다: %s", got)
+	}
+	var declarations []string
+	for _, declaration := range order.Declarations {
+		declarations = append(declarations, fmt.Sprintf("%s:%d-%d", declaration.File, declaration.Range.StartLine, declaration.Range.EndLine))
+	}
+	if got := strings.Join(declarations, ","); got != "src/Generated/Order.g.cs:4-8,src/Order.Validation.cs:3-6,src/Order.cs:3-6" {
+		t.Errorf("선언 위치가 올바르지 않습니다: %s", got)
+	}
+
+	// 상속 목록과 특성은 모든 선언을 중복 없이 합침
+	if got := strings.Join(append(order.Skeleton.Extends, order.Skeleton.Implements...), ","); got != "Entity,IValidatable,IDisposable" {
+		t.Errorf("상속 목록이 올바르지 않습니다: extends=%v implements=%v", order.Skeleton.Extends, order.Skeleton.Implements)
+	}
+	if len(order.Skeleton.Attributes) != 1 || order.Skeleton.Visibility != "public" || order.Skeleton.Range != nil {
+		t.Errorf("병합된 타입 정보가 올바르지 않습니다: %+v", order.Skeleton)
+	}
+
+	// 멤버는 선언된 파일과 함께 모두 포함
+	var members []string
+	for _, member := range order.Skeleton.Members {
+		members = append(members, member.Name+"@"+member.File)
+	}
+	if got := strings.Join(members, ","); got != "Dispose@src/Generated/Order.g.cs,Describe@src/Generated/Order.g.cs,Validate@src/Order.Validation.cs,Id@src/Order.cs" {
+		t.Errorf("병합된 멤버가 올바르지 않습니다: %s", got)
+	}
+}
+
+func TestMergePartialTypesNestedVisibility(t *testing.T) {
+	explicit := "namespace Shop\n" +
+		"{\n" +
+		"    partial class Outer\n" +
+		"    {\n" +
+		"        public partial class Inner { public void A() { } }\n" +
+		"    }\n" +
+		"}\n"
+	implicit := "namespace Shop\n" +
+		"{\n" +
+		"    partial class Outer\n" +
+		"    {\n" +
+		"        partial class Inner { public void B() { } }\n" +
+		"    }\n" +
+		"}\n"
+	// 파일 순서가 바뀌어도 생략된 쪽의 기본값(private)이 명시된 public을 덮어쓰면 안 됨
+	for _, files := range [][2]string{{"A.cs", "B.cs"}, {"B.cs", "A.cs"}} {
+		var results []*model.AnalysisResult
+		for i, source := range []string{explicit, implicit} {
+			nodes, _, err := parser.NewCSharpParser().Parse(source)
+			if err != nil {
+				t.Fatalf("파싱 오류: %v", err)
+			}
+			results = append(results, &model.AnalysisResult{Path: "src", Filename: files[i], Skeleton: nodes})
+		}
+
+		partials := project.MergePartialTypes(results)
+		if len(partials) != 2 || partials[1].QualifiedName != "Shop.Outer.Inner" {
+			t.Fatalf("%s 순서: 중첩 partial 타입이 병합되지 않았습니다: %+v", files[0], partials)
+		}
+		if got := partials[0].Skeleton.Visibility; got != "internal" {
+			t.Errorf("%s 순서: Outer 접근 제한자가 올바르지 않습니다: %s", files[0], got)
+		}
+		if got := partials[1].Skeleton.Visibility; got != "public" {
+			t.Errorf("%s 순서: Inner 접근 제한자가 올바르지 않습니다: %s", files[0], got)
+		}
+	}
+}
+
+func TestParserFactoryConfigure(t *testing.T) {
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+	factory.RegisterParser(parser.NewJavaScriptParser())
+
+	// parserFor는 확장자에 연결된 파서 이름을 반환합니다 (없으면 빈 문자열)
+	parserFor := func(ext string) string {
+		p, err := factory.GetParser(ext)
+		if err != nil {
+			return ""
+		}
+		return p.GetName()
+	}
+
+	// 등록되지 않은 파서 이름은 모두 모아 거부하고, 매핑은 적용하지 않음
+	err := factory.Configure(map[string]string{
+		".csx": "csharp_parser",
+		".py":  "python_parser",
+		".rb":  "ruby_parser",
+	})
+	if err == nil {
+		t.Fatal("등록되지 않은 파서 이름이 허용되었습니다")
+	}
+	for _, want := range []string{"python_parser (.py)", "ruby_parser (.rb)", "available: csharp_parser, javascript_parser"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("오류 메시지에 %q가 없습니다: %v", want, err)
+		}
+	}
+	if parserFor(".cs") != "csharp_parser" || parserFor(".js") != "javascript_parser" || parserFor(".csx") != "" {
+		t.Error("거부된 설정의 매핑이 적용되었습니다")
+	}
+
+	// 빈 설정은 파서의 기본 확장자를 유지
+	if err := factory.Configure(nil); err != nil || parserFor(".cs") != "csharp_parser" {
+		t.Errorf("빈 설정이 기본 매핑을 바꾸었습니다: %v", err)
+	}
+
+	// 추가 확장자 연결과 기본 매핑 덮어쓰기 (설정하면 설정의 확장자만 사용)
+	err = factory.Configure(map[string]string{
+		".csx": "csharp_parser",
+		".mjs": "javascript_parser",
+		".js":  "csharp_parser",
+	})
+	if err != nil {
+		t.Fatalf("파서 설정 오류: %v", err)
+	}
+	for ext, want := range map[string]string{
+		".csx": "csharp_parser",
+		".mjs": "javascript_parser",
+		".js":  "csharp_parser",
+		".cs":  "",
+	} {
+		if got := parserFor(ext); got != want {
+			t.Errorf("%s: 예상 파서 %q, 실제 %q", ext, want, got)
+		}
+	}
+	if p, err := factory.ParserForFile("scripts/build.csx", nil); err != nil || p.GetName() != "csharp_parser" {
+		t.Errorf(".csx 파일에 C# 파서가 선택되지 않았습니다: %v", err)
+	}
+
+	// 설정으로 정의한 파서의 이름이 비어 있거나 이미 등록된 이름이면 거부하고, 기존 파서는 유지
+	invalid := parser.NewParserFactory()
+	invalid.RegisterParser(parser.NewCSharpParser())
+	for _, name := range []string{"", "csharp_parser"} {
+		ruleParser, err := parser.NewRuleParser(parser.RuleParserConfig{
+			Name:     name,
+			Language: "Pascal",
+			Rules:    []parser.RuleConfig{{Type: "function", Pattern: `^\s*procedure\s+(\w+)`}},
+		})
+		if err != nil {
+			t.Fatalf("규칙 기반 파서 생성 오류: %v", err)
+		}
+		invalid.RegisterParser(ruleParser)
+	}
+	err = invalid.Configure(nil)
+	if err == nil || !strings.Contains(err.Error(), "empty name (Pascal)") || !strings.Contains(err.Error(), "duplicate name csharp_parser") {
+		t.Errorf("빈 이름이나 중복 이름의 파서가 허용되었습니다: %v", err)
+	}
+	if p, err := invalid.GetParserByName("csharp_parser"); err != nil || p.GetLanguage() != "C#" {
+		t.Errorf("중복 이름의 파서가 기존 파서를 덮어썼습니다: %v", p)
+	}
+}
+
+func TestJavaScriptParserMasking(t *testing.T) {
+	// 정규식 리터럴 안의 /* 는 주석이 아니고, 닫히지 않은 주석은 파일 끝까지
+	sources := map[string]string{
+		"정규식": "function clean(s) {\n  return s.replace(/\\/*$/, '').split(/[/]/);\n}\nfunction half(a, b) {\n  return (a + b) / 2 / a;\n}\n",
+		"주석":  "function run() {\n  start();\n}\n/* 닫히지 않은 주석",
+	}
+	for name, source := range sources {
+		nodes, _, err := parser.NewJavaScriptParser().Parse(source)
+		if err != nil {
+			t.Fatalf("%s: 파싱 중 오류 발생: %v", name, err)
+		}
+		if len(nodes) == 0 || nodes[0].Range == nil || nodes[0].Range.EndLine != 3 {
+			t.Errorf("%s: 함수 범위가 올바르지 않습니다: %+v", name, nodes)
+		}
+	}
+
+	nodes, _, _ := parser.NewJavaScriptParser().Parse(sources["정규식"])
+	var calls []string
+	for _, call := range nodes[0].Calls {
+		calls = append(calls, call.Name)
+	}
+	if strings.Join(calls, ",") != "replace,split" {
+		t.Errorf("정규식 뒤의 호출이 검출되지 않았습니다: %v", calls)
+	}
+	if len(nodes) != 2 || nodes[1].Range.EndLine != 6 {
+		t.Errorf("나누기 연산자를 정규식으로 처리하면 안 됩니다: %+v", nodes)
+	}
+
+	// 메서드 안 문자열의 중괄호는 클래스 본문의 깊이에 포함하지 않음
+	classSource := "class View {\n" +
+		"  open() {\n" +
+		"    return \"{\" + '{{';\n" +
+		"  }\n" +
+		"  close() {\n" +
+		"    return `}`;\n" +
+		"  }\n" +
+		"}\n" +
+		"function after() {\n" +
+		"}\n"
+	classNodes, _, err := parser.NewJavaScriptParser().Parse(classSource)
+	if err != nil {
+		t.Fatalf("파싱 중 오류 발생: %v", err)
+	}
+	if len(classNodes) != 2 || len(classNodes[0].Members) != 2 || classNodes[0].Members[1].Name != "close" || classNodes[0].Range.EndLine != 8 {
+		t.Errorf("문자열 안의 중괄호로 클래스 범위가 바뀌었습니다: %+v", classNodes)
+	}
+}
+
+func TestParserDetection(t *testing.T) {
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+	factory.RegisterParser(parser.NewJavaScriptParser())
+	if err := factory.Configure(map[string]string{".cs": "csharp_parser", ".h": "csharp_parser"}); err != nil {
+		t.Fatalf("파서 설정 오류: %v", err)
+	}
+	err := factory.ConfigureDetection(parser.DetectionConfig{
+		Filenames: map[string]string{"Jakefile": "javascript_parser", "*.cake": "csharp_parser"},
+		Content: []parser.ContentRule{
+			{Extensions: []string{".h"}, Pattern: `(?m)^\s*(module\.exports|export )`, Parser: "javascript_parser"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("판별 규칙 설정 오류: %v", err)
+	}
+
+	tests := []struct {
+		path, content, expected string
+	}{
+		{"Jakefile", "task('default', function () {});", "javascript_parser"},
+		{"build.cake", "Task(\"Default\");", "csharp_parser"},
+		{"tool", "#!/usr/bin/env node\nconsole.log(1);", "javascript_parser"},
+		{"script", "// vim: set ft=csharp:\nclass A {}", "csharp_parser"},
+		{"shim.h", "module.exports = {};", "javascript_parser"},
+		{"native.h", "#pragma once\nstruct A {};", "csharp_parser"},
+		{"Program.cs", "#!/usr/bin/env node", "csharp_parser"},
+	}
+	for _, tt := range tests {
+		p, err := factory.ParserForFile(tt.path, []byte(tt.content))
+		if err != nil {
+			t.Errorf("%s: 파서를 찾지 못했습니다: %v", tt.path, err)
+			continue
+		}
+		if p.GetName() != tt.expected {
+			t.Errorf("%s: 예상 파서 %s, 실제 %s", tt.path, tt.expected, p.GetName())
+		}
+	}
+
+	if _, err := factory.ParserForFile("README", []byte("plain text")); err == nil {
+		t.Error("판별할 수 없는 파일에 파서가 선택되었습니다")
+	}
+	if _, err := factory.ParserForFile("app.js", nil); err == nil {
+		t.Error("설정에 없는 확장자에 파서가 선택되었습니다")
+	}
+
+	// 앞부분(8KB)을 넘는 파일도 마지막 줄의 모드라인을 확인
+	large := strings.Repeat("console.log('padding');\n", 1000) + "// vim: set ft=javascript:\n"
+	largePath := filepath.Join(t.TempDir(), "large-script")
+	if err := ioutil.WriteFile(largePath, []byte(large), 0644); err != nil {
+		t.Fatalf("파일 쓰기 오류: %v", err)
+	}
+	if p, err := factory.DetectFile(largePath); err != nil || p.GetName() != "javascript_parser" {
+		t.Errorf("큰 파일 끝의 모드라인을 찾지 못했습니다: %v", err)
+	}
+	if p, err := factory.ParserForFile(largePath, []byte(large)); err != nil || p.GetName() != "javascript_parser" {
+		t.Errorf("큰 파일 내용 끝의 모드라인을 찾지 못했습니다: %v", err)
+	}
+
+	if err := factory.ConfigureDetection(parser.DetectionConfig{Shebangs: map[string]string{"python": "python_parser"}}); err == nil {
+		t.Error("등록되지 않은 파서 이름이 허용되었습니다")
+	}
+}
+
+func TestParserDetectionOverlappingGlobs(t *testing.T) {
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+	factory.RegisterParser(parser.NewJavaScriptParser())
+
+	tests := []struct {
+		path, expected string
+	}{
+		{"Form.Designer.cs", "javascript_parser"}, // *.Designer.cs가 *.cs보다 구체적
+		{"Program.cs", "csharp_parser"},
+		{"Form.g.Designer.cs", "csharp_parser"}, // *.g.Designer.cs가 가장 구체적
+		{"aa.txt", "csharp_parser"},             // 글자 수가 같으면 패턴 순서 (*a.txt < a*.txt)
+	}
+	// 규칙은 map이므로 여러 번 설정해도 같은 결과가 나와야 함
+	for i := 0; i < 20; i++ {
+		err := factory.ConfigureDetection(parser.DetectionConfig{
+			Filenames: map[string]string{
+				"*.cs":            "csharp_parser",
+				"*.Designer.cs":   "javascript_parser",
+				"*.g.Designer.cs": "csharp_parser",
+				"*a.txt":          "csharp_parser",
+				"a*.txt":          "javascript_parser",
+			},
+		})
+		if err != nil {
+			t.Fatalf("판별 규칙 설정 오류: %v", err)
+		}
+		for _, tt := range tests {
+			p, err := factory.ParserForFile(tt.path, nil)
+			if err != nil {
+				t.Fatalf("%s: 파서를 찾지 못했습니다: %v", tt.path, err)
+			}
+			if p.GetName() != tt.expected {
+				t.Fatalf("%s: 예상 파서 %s, 실제 %s", tt.path, tt.expected, p.GetName())
+			}
+		}
+	}
+}
+
+func TestPluginParser(t *testing.T) {
+	// 참조 플러그인 빌드
+	_, filename, _, _ := runtime.Caller(0)
+	pluginPath := filepath.Join(t.TempDir(), "markdown_parser")
+	build := exec.Command("go", "build", "-o", pluginPath, "./src/plugins/markdown_parser")
+	build.Dir = filepath.Dir(filepath.Dir(filename))
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("참조 플러그인 빌드 실패: %v\n%s", err, output)
+	}
+
+	p := parser.NewPluginParser(parser.PluginConfig{
+		Name:       "markdown_parser",
+		Language:   "markdown",
+		Command:    pluginPath,
+		Extensions: []string{".md"},
+	})
+	source := "# 소개\n본문\n\n## 설치\n```\n# 주석\n```\n"
+	nodes, chunks, err := p.Parse(source)
+	if err != nil {
+		t.Fatalf("플러그인 파싱 오류: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Name != "소개" || nodes[1].Name != "설치" {
+		t.Fatalf("섹션이 올바르지 않습니다: %+v", nodes)
+	}
+	if len(chunks) != 2 || chunks[0].MD5 == "" || chunks[1].Range.StartLine != 4 || chunks[1].Range.EndLine != 7 {
+		t.Errorf("청크가 올바르지 않습니다: %+v", chunks)
+	}
+	if source[chunks[1].Range.Start:chunks[1].Range.End] != chunks[1].Text {
+		t.Errorf("청크 오프셋이 올바르지 않습니다: %+v", chunks[1].Range)
+	}
+
+	// 비정상 종료한 플러그인은 stderr와 함께 오류를 반환
+	crash := parser.NewPluginParser(parser.PluginConfig{
+		Name:    "crash_parser",
+		Command: "sh",
+		Args:    []string{"-c", "echo boom >&2; exit 3"},
+	})
+	if _, _, err := crash.Parse("x"); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("비정상 종료 오류가 올바르지 않습니다: %v", err)
+	}
+
+	// 제한 시간을 넘긴 플러그인은 중단
+	slow := parser.NewPluginParser(parser.PluginConfig{
+		Name:    "slow_parser",
+		Command: "sh",
+		Args:    []string{"-c", "sleep 5"},
+		Timeout: 200 * time.Millisecond,
+	})
+	started := time.Now()
+	if _, _, err := slow.Parse("x"); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("제한 시간 오류가 올바르지 않습니다: %v", err)
+	}
+	if time.Since(started) > 3*time.Second {
+		t.Errorf("제한 시간이 지켜지지 않았습니다: %s", time.Since(started))
+	}
+}
+
+func TestRuleParser(t *testing.T) {
+	// VB6: 대소문자 구분 없는 시작/종료 키워드, ' 주석, "" 문자열
+	vb, err := parser.NewRuleParser(parser.RuleParserConfig{
+		Name:            "vb6_parser",
+		Block:           parser.BlockKeywords,
+		CaseInsensitive: true,
+		LineComments:    []string{"'"},
+		Strings:         []string{`"`},
+		Rules: []parser.RuleConfig{
+			{Type: "class", Pattern: `^\s*Class\s+(\w+)`, End: `^\s*End\s+Class\b`, Container: true},
+			{Type: "method", Pattern: `^\s*(?:Public\s+|Private\s+)?(?:Function|Sub)\s+(?P<name>\w+)`, End: `^\s*End\s+(?:Function|Sub)\b`},
+		},
+	})
+	if err != nil {
+		t.Fatalf("규칙 파서 생성 오류: %v", err)
+	}
+	source := "Class Greeter\n" +
+		"  Public Sub Hello()\n" +
+		"    ' Sub Fake()\n" +
+		"    MsgBox \"End Sub\"\n" +
+		"  End Sub\n" +
+		"End Class\n" +
+		"\n" +
+		"function Helper(x)\n" +
+		"  Helper = x\n" +
+		"end function\n"
+	nodes, chunks, err := vb.Parse(source)
+	if err != nil {
+		t.Fatalf("파싱 오류: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Name != "Greeter" || nodes[1].Name != "Helper" || nodes[1].Type != "method" {
+		t.Fatalf("노드가 올바르지 않습니다: %+v", nodes)
+	}
+	if len(nodes[0].Members) != 1 || nodes[0].Members[0].Name != "Hello" || nodes[0].Members[0].Range.EndLine != 5 {
+		t.Errorf("멤버가 올바르지 않습니다: %+v", nodes[0].Members)
+	}
+	if nodes[0].Range.EndLine != 6 || len(chunks) != 2 || chunks[1].Range.StartLine != 8 || chunks[1].Range.EndLine != 10 {
+		t.Errorf("범위가 올바르지 않습니다: %+v %+v", nodes[0].Range, chunks)
+	}
+
+	// 들여쓰기 블록
+	py, err := parser.NewRuleParser(parser.RuleParserConfig{
+		Name:         "py_rule_parser",
+		Block:        parser.BlockIndent,
+		LineComments: []string{"#"},
+		Strings:      []string{`"`, `'`},
+		StringEscape: `\`,
+		Rules: []parser.RuleConfig{
+			{Type: "class", Pattern: `^\s*class\s+(\w+)`, Container: true},
+			{Type: "function", Pattern: `^\s*def\s+(\w+)`},
+		},
+	})
+	if err != nil {
+		t.Fatalf("규칙 파서 생성 오류: %v", err)
+	}
+	source = "class A:\n    def f(self):\n        return '{'\n\n    def g(self):\n        pass\n\ndef main():\n    A()\n"
+	nodes, chunks, _ = py.Parse(source)
+	if len(nodes) != 2 || len(nodes[0].Members) != 2 || nodes[0].Members[0].Range.EndLine != 3 || nodes[1].Name != "main" {
+		t.Errorf("들여쓰기 블록이 올바르지 않습니다: %+v", nodes)
+	}
+	if len(chunks) != 3 || chunks[2].Text != "def main():\n    A()" {
+		t.Errorf("청크가 올바르지 않습니다: %+v", chunks)
+	}
+
+	if _, err := parser.NewRuleParser(parser.RuleParserConfig{Name: "bad", Rules: []parser.RuleConfig{{Type: "x", Pattern: "("}}}); err == nil {
+		t.Error("잘못된 정규식이 허용되었습니다")
+	}
+}
+
+func TestImports(t *testing.T) {
+	csharp := "global using System;\nusing Svc = Shop.Orders;\n\nnamespace Shop.Api {\n  class C { void M() { using var x = Open(); } }\n}\n"
+	usings := parser.NewCSharpParser().ExtractImports(csharp)
+	if len(usings) != 2 || usings[0].Path != "System" || usings[0].Modifiers[0] != "global" || usings[1].Alias != "Svc" || usings[1].Range.StartLine != 2 {
+		t.Fatalf("using 구문이 올바르지 않습니다: %+v", usings)
+	}
+
+	js := "import a, { b, c as d } from './lib';\nconst { e } = require(\"./e\");\n"
+	imports := parser.NewJavaScriptParser().ExtractImports(js)
+	if len(imports) != 2 || imports[0].Path != "./lib" || strings.Join(imports[0].Names, ",") != "a,b,c" || imports[1].Kind != "require" || imports[1].Names[0] != "e" {
+		t.Fatalf("import 구문이 올바르지 않습니다: %+v", imports)
+	}
+
+	results := []*model.AnalysisResult{
+		{Path: "web", Filename: "app.js", Imports: imports},
+		{Path: filepath.Join("web", "lib"), Filename: "index.js"},
+		{Path: "api", Filename: "C.cs", Imports: usings},
+		{Path: "orders", Filename: "Order.cs", Skeleton: []model.SkeletonNode{{Type: "class", Name: "Order", Namespace: "Shop.Orders"}}},
+	}
+	project.ResolveImports(results)
+	if got := results[0].Imports[0].Resolved; len(got) != 1 || got[0] != filepath.Join("web", "lib", "index.js") {
+		t.Errorf("모듈 경로가 연결되지 않았습니다: %v", got)
+	}
+	if got := results[0].Imports[1].Resolved; len(got) != 0 {
+		t.Errorf("없는 모듈이 연결되었습니다: %v", got)
+	}
+	if got := results[2].Imports[1].Resolved; len(got) != 1 || got[0] != filepath.Join("orders", "Order.cs") {
+		t.Errorf("네임스페이스가 연결되지 않았습니다: %v", got)
+	}
+}
+
+func TestCallGraph(t *testing.T) {
+	csharp := "namespace Shop {\n" +
+		"  class Repo { public Repo() { } public void Save(int x) { } }\n" +
+		"  class Service : BaseService {\n" +
+		"    void Run(bool ok) {\n" +
+		"      var repo = new Repo();\n" +
+		"      repo.Save(ok ? Compute() : 0);\n" +
+		"      Log(\"Run()\");\n" +
+		"      Console.WriteLine(1);\n" +
+		"      int Local(int y) { return y; }\n" +
+		"    }\n" +
+		"    int Compute() { return Helper.Max<int>(1, 2); }\n" +
+		"  }\n" +
+		"  class BaseService { protected void Log(string m) { } }\n" +
+		"  static class Helper { public static T Max<T>(T a, T b) { return a; } }\n" +
+		"}\n"
+	nodes, _, err := parser.NewCSharpParser().Parse(csharp)
+	if err != nil {
+		t.Fatalf("파싱 오류: %v", err)
+	}
+	var run model.Member
+	for _, node := range nodes {
+		for _, member := range node.Members {
+			if member.Name == "Run" {
+				run = member
+			}
+		}
+	}
+	var names []string
+	for _, call := range run.Calls {
+		names = append(names, call.Receiver+"."+call.Name+":"+call.Kind)
+	}
+	if strings.Join(names, ",") != ".Repo:new,repo.Save:,.Compute:,.Log:,Console.WriteLine:" {
+		t.Fatalf("C# 호출 위치가 올바르지 않습니다: %v", names)
+	}
+	if call := run.Calls[1]; csharp[call.Range.Start:call.Range.End] != "Save" {
+		t.Errorf("호출 범위가 올바르지 않습니다: %+v", call.Range)
+	}
+
+	js := "function run() {\n  // skip() in comment\n  const w = new Widget();\n  w.draw().render();\n  return helper(\"x()\");\n}\n"
+	jsNodes, _, err := parser.NewJavaScriptParser().Parse(js)
+	if err != nil {
+		t.Fatalf("파싱 오류: %v", err)
+	}
+	names = nil
+	for _, call := range jsNodes[0].Calls {
+		names = append(names, call.Receiver+"."+call.Name+":"+call.Kind)
+	}
+	if strings.Join(names, ",") != ".Widget:new,w.draw:,.render:member,.helper:" || jsNodes[0].Range.EndLine != 6 {
+		t.Fatalf("JavaScript 호출 위치가 올바르지 않습니다: %v", names)
+	}
+
+	graph := project.BuildCallGraph([]*model.AnalysisResult{{Path: "src", Filename: "Shop.cs", Skeleton: nodes}})
+	byID := make(map[string]project.CallGraphNode)
+	for _, node := range graph.Nodes {
+		byID[node.ID] = node
+	}
+	if got := strings.Join(byID["Shop.Service.Run"].Callees, ","); got != "Shop.Repo.Repo,Shop.Repo.Save,Shop.Service.Compute,Shop.BaseService.Log" {
+		t.Errorf("호출 대상이 올바르지 않습니다: %s", got)
+	}
+	if got := byID["Shop.Service.Run"].Unresolved; len(got) != 1 || got[0] != "Console.WriteLine" {
+		t.Errorf("연결되지 않은 호출이 올바르지 않습니다: %v", got)
+	}
+	if got := byID["Shop.Helper.Max"].Callers; len(got) != 1 || got[0] != "Shop.Service.Compute" {
+		t.Errorf("호출자가 올바르지 않습니다: %v", got)
+	}
+}
+
+func TestSymbolIndex(t *testing.T) {
+	csharp := "// 주석\nclass Repo {\n  void Save() { Save(); }\n}\n"
+	identifiers := parser.NewCSharpParser().ExtractIdentifiers(csharp)
+	if len(identifiers) != 3 || identifiers[2].Name != "Save" || identifiers[2].Line != 3 || identifiers[2].Column != 17 {
+		t.Fatalf("C# 식별자가 올바르지 않습니다: %+v", identifiers)
+	}
+	if csharp[identifiers[2].Start:identifiers[2].End] != "Save" {
+		t.Errorf("식별자 범위가 올바르지 않습니다: %+v", identifiers[2])
+	}
+
+	js := "const 값 = 'Repo'; // Repo\nlet r = new Repo(); r.Save();\n"
+	jsIdentifiers := parser.NewJavaScriptParser().ExtractIdentifiers(js)
+	var names []string
+	for _, identifier := range jsIdentifiers {
+		names = append(names, identifier.Name)
+	}
+	if strings.Join(names, ",") != "r,Repo,r,Save" || jsIdentifiers[1].Column != 13 {
+		t.Fatalf("JavaScript 식별자가 올바르지 않습니다: %+v", jsIdentifiers)
+	}
+
+	nodes, _, err := parser.NewCSharpParser().Parse(csharp)
+	if err != nil {
+		t.Fatalf("파싱 오류: %v", err)
+	}
+	index := project.BuildSymbolIndex([]*model.AnalysisResult{
+		{Path: "api", Filename: "Repo.cs", Skeleton: nodes, Identifiers: identifiers},
+		{Path: "web", Filename: "app.js", Identifiers: jsIdentifiers},
+	})
+	if defs := index.Definitions("Repo.Save"); len(defs) != 1 || defs[0].Type != "method" || defs[0].Range.StartLine != 3 {
+		t.Errorf("선언을 찾지 못했습니다: %+v", defs)
+	}
+	refs := index.FindReferences("Repo.Save")
+	if len(refs) != 3 || refs[2].File != filepath.Join("web", "app.js") || refs[2].Line != 2 {
+		t.Errorf("참조 위치가 올바르지 않습니다: %+v", refs)
+	}
+	if refs := index.FindReferences("r"); len(refs) != 0 {
+		t.Errorf("선언되지 않은 이름이 색인되었습니다: %+v", refs)
+	}
+}
+
+func TestTypeHierarchy(t *testing.T) {
+	source := "namespace Pay {\n" +
+		"  interface IGateway { }\n" +
+		"  interface IRefundable : IGateway { }\n" +
+		"  abstract class GatewayBase : IGateway, IDisposable { }\n" +
+		"  class CardGateway : GatewayBase, IRefundable { }\n" +
+		"}\n"
+	nodes, _, err := parser.NewCSharpParser().Parse(source)
+	if err != nil {
+		t.Fatalf("파싱 오류: %v", err)
+	}
+	hierarchy := project.BuildTypeHierarchy([]*model.AnalysisResult{{Path: "src", Filename: "Pay.cs", Skeleton: nodes}})
+
+	names := func(types []project.TypeHierarchyNode) string {
+		var list []string
+		for _, node := range types {
+			list = append(list, node.Name)
+		}
+		return strings.Join(list, ",")
+	}
+	if got := names(hierarchy.Implementers("IGateway")); got != "Pay.GatewayBase,Pay.CardGateway" && got != "Pay.CardGateway,Pay.GatewayBase" {
+		t.Errorf("구현체가 올바르지 않습니다: %s", got)
+	}
+	if got := names(hierarchy.Descendants("Pay.IGateway")); !strings.Contains(got, "Pay.IRefundable") {
+		t.Errorf("하위 타입이 올바르지 않습니다: %s", got)
+	}
+	if got := names(hierarchy.Ancestors("Pay.CardGateway")); got != "Pay.GatewayBase,Pay.IRefundable,Pay.IGateway,IDisposable" {
+		t.Errorf("상위 타입이 올바르지 않습니다: %s", got)
+	}
+	if found := hierarchy.Find("IDisposable"); len(found) != 1 || !found[0].External {
+		t.Errorf("외부 타입이 올바르지 않습니다: %+v", found)
+	}
+}
+
+func TestDiagram(t *testing.T) {
+	source := "namespace Pay {\n" +
+		"  interface IGateway { void Charge(); }\n" +
+		"  class CardGateway : IGateway { public void Charge() { Log(); } void Log() { } }\n" +
+		"}\n"
+	nodes, _, err := parser.NewCSharpParser().Parse(source)
+	if err != nil {
+		t.Fatalf("파싱 오류: %v", err)
+	}
+	results := []*model.AnalysisResult{
+		{Path: "pay", Filename: "Card.cs", Skeleton: nodes},
+		{Path: "web", Filename: "app.js", Imports: []model.Import{{Kind: "import", Path: "./x", Resolved: []string{filepath.Join("pay", "Card.cs")}}}},
+	}
+
+	classes := project.ClassDiagram(project.BuildTypeHierarchy(results), project.BuildSymbolIndex(results), project.DiagramScope{Namespace: "Pay"})
+	dot := classes.DOT()
+	if !strings.Contains(dot, `"Pay.CardGateway" -> "Pay.IGateway" [arrowhead=empty, style=dashed];`) || !strings.Contains(dot, `Charge()\lLog()\l`) {
+		t.Errorf("클래스 다이어그램(DOT)이 올바르지 않습니다:\n%s", dot)
+	}
+	if mermaid := classes.Mermaid(); !strings.Contains(mermaid, "classDiagram") || !strings.Contains(mermaid, "<|..") {
+		t.Errorf("클래스 다이어그램(Mermaid)이 올바르지 않습니다:\n%s", mermaid)
+	}
+
+	packages := project.PackageDiagram(project.BuildDependencyGraph(results), false, project.DiagramScope{Root: "web"})
+	if len(packages.Edges) != 1 || packages.Edges[0].From != "web" || packages.Edges[0].To != "pay" {
+		t.Errorf("패키지 다이어그램이 올바르지 않습니다: %+v", packages.Edges)
+	}
+
+	calls := project.CallDiagram(project.BuildCallGraph(results), project.DiagramScope{Root: "CardGateway.Charge", Depth: 1})
+	if mermaid := calls.Mermaid(); len(calls.Nodes) != 2 || !strings.Contains(mermaid, "flowchart LR") || !strings.Contains(mermaid, "-->") {
+		t.Errorf("호출 다이어그램이 올바르지 않습니다:\n%s", mermaid)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for i := 0; i < 20; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("File%02d.cs", i))
+		source := fmt.Sprintf("class Class%d { void Run() { } }\n", i)
+		if err := ioutil.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatalf("파일 쓰기 오류: %v", err)
+		}
+		files = append(files, path)
+	}
+	// 탐색 순서를 파일 이름의 역순으로 하고, 존재하지 않는 파일도 하나 넘김
+	missing := filepath.Join(dir, "Missing.cs")
+
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+	pipeline := analyzer.NewPipeline(analyzer.NewAnalyzer(factory, nil, nil), analyzer.PipelineConfig{ParseWorkers: 8})
+	results, errs := pipeline.Run([]string{dir}, func(folder string, visit func(path string)) error {
+		for i := len(files) - 1; i >= 0; i-- {
+			visit(files[i])
+			if i == 10 {
+				visit(missing)
+			}
+		}
+		return nil
+	})
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "Missing.cs") {
+		t.Errorf("존재하지 않는 파일의 오류만 반환되어야 합니다: %v", errs)
+	}
+	if len(results) != len(files) {
+		t.Fatalf("결과 수가 올바르지 않습니다: %d", len(results))
+	}
+	for i, result := range results {
+		want := fmt.Sprintf("File%02d.cs", len(files)-1-i)
+		if result.Filename != want || len(result.Skeleton) != 1 {
+			t.Errorf("결과 %d는 탐색 순서대로 %s여야 합니다: %s", i, want, result.Filename)
+		}
+	}
+
+	// 실패한 파일을 포함한 모든 파일의 처리 결과
+	reports := pipeline.Reports()
+	if len(reports) != len(files)+1 {
+		t.Fatalf("모든 파일의 처리 결과가 있어야 합니다: %d", len(reports))
+	}
+	var manifestFiles []project.ManifestFile
+	for _, report := range reports {
+		file := project.ManifestFile{File: filepath.Base(report.Path), Parser: report.Parser, Status: project.ManifestAnalyzed}
+		if report.Err != nil {
+			file.Status = project.ManifestFailed
+		} else {
+			file.Chunks = len(report.Result.Chunks)
+		}
+		if report.Parser != "csharp_parser" || report.Language != "C#" {
+			t.Errorf("%s의 파서가 기록되어야 합니다: %s (%s)", report.Path, report.Parser, report.Language)
+		}
+		manifestFiles = append(manifestFiles, file)
+	}
+	manifest := project.NewManifest("out", "", manifestFiles)
+	if manifest.Totals.Files != 21 || manifest.Totals.Failed != 1 || manifest.Totals.Analyzed != 20 || manifest.Totals.Chunks < 20 {
+		t.Errorf("매니페스트 합계가 올바르지 않습니다: %+v", manifest.Totals)
+	}
+	if manifest.Files[0].File != "File00.cs" || manifest.Files[20].File != "Missing.cs" {
+		t.Errorf("매니페스트 파일은 경로 순서로 정렬되어야 합니다: %s, %s", manifest.Files[0].File, manifest.Files[20].File)
+	}
+	if len(pipeline.WalkErrors()) != 0 {
+		t.Errorf("탐색 오류가 없어야 합니다: %v", pipeline.WalkErrors())
+	}
+
+	// 탐색에 실패한 폴더는 찾은 파일까지만 분석하고 탐색 오류로 구분
+	results, errs = pipeline.Run([]string{dir}, func(folder string, visit func(path string)) error {
+		visit(files[0])
+		return os.ErrPermission
+	})
+	if len(results) != 1 || len(errs) != 1 || len(pipeline.WalkErrors()) != 1 {
+		t.Errorf("탐색 오류가 기록되어야 합니다: %d, %v", len(results), pipeline.WalkErrors())
+	}
+}
+
+// fakeEmbedding은 요청마다 텍스트에 번호를 매겨 [번호]를 임베딩으로 돌려주는 테스트용 임베딩 서비스입니다.
+type fakeEmbedding struct {
+	mu       sync.Mutex
+	texts    []string
+	requests []int // 요청별 텍스트 수
+}
+
+func (f *fakeEmbedding) CreateEmbedding(text string) ([]float32, error) {
+	vectors, err := f.CreateEmbeddings([]string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (f *fakeEmbedding) CreateEmbeddings(texts []string) ([][]float32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, len(texts))
+	var vectors [][]float32
+	for _, text := range texts {
+		vectors = append(vectors, []float32{float32(len(f.texts))})
+		f.texts = append(f.texts, text)
+	}
+	return vectors, nil
+}
+
+func (f *fakeEmbedding) ChunkText(text string, maxSize int) ([]string, error) {
+	return []string{text}, nil
+}
+
+// panicParser는 특정 내용의 파일에서 panic을 일으키는 테스트용 파서입니다.
+type panicParser struct{}
+
+func (panicParser) Parse(sourceCode string) ([]model.SkeletonNode, []model.Chunk, error) {
+	if strings.Contains(sourceCode, "panic") {
+		var nodes []model.SkeletonNode
+		_ = nodes[len(sourceCode)]
+	}
+	return nil, []model.Chunk{{MD5: "md5", Text: sourceCode}}, nil
+}
+func (panicParser) GetName() string             { return "panic_parser" }
+func (panicParser) GetLanguage() string         { return "Test" }
+func (panicParser) GetFileExtensions() []string { return []string{".txt"} }
+
+func TestPipelineParserPanic(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for _, name := range []string{"a", "panic", "b"} {
+		path := filepath.Join(dir, name+".txt")
+		ioutil.WriteFile(path, []byte(name), 0644)
+		files = append(files, path)
+	}
+
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(panicParser{})
+	pipeline := analyzer.NewPipeline(analyzer.NewAnalyzer(factory, nil, nil), analyzer.PipelineConfig{ParseWorkers: 2})
+	results, errs := pipeline.Run([]string{dir}, func(folder string, visit func(path string)) error {
+		for _, path := range files {
+			visit(path)
+		}
+		return nil
+	})
+
+	if len(results) != 2 {
+		t.Errorf("panic이 발생하지 않은 파일은 모두 분석되어야 합니다: %d", len(results))
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "panic.txt") || !strings.Contains(errs[0].Error(), "parser panic") {
+		t.Errorf("panic은 해당 파일의 오류로 반환되어야 합니다: %v", errs)
+	}
+}
+
+func TestPipelineEmbeddingBatches(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		source := fmt.Sprintf("class Class%d {\n  void Run%d() { }\n  void Stop%d() { }\n}\n", i, i, i)
+		if err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("File%02d.cs", i)), []byte(source), 0644); err != nil {
+			t.Fatalf("파일 쓰기 오류: %v", err)
+		}
+	}
+
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+	service := &fakeEmbedding{}
+	config := &embeddings.Config{MaxTextSize: 1024, BatchSize: 8, BatchTokens: 100000}
+	pipeline := analyzer.NewPipeline(analyzer.NewAnalyzer(factory, service, config), analyzer.PipelineConfig{ParseWorkers: 4, EmbedWorkers: 3})
+	results, errs := pipeline.Run([]string{dir}, func(folder string, visit func(path string)) error {
+		return filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				visit(path)
+			}
+			return err
+		})
+	})
+	if len(errs) != 0 || len(results) != 20 {
+		t.Fatalf("모든 파일이 분석되어야 합니다: %d개, 오류 %v", len(results), errs)
+	}
+
+	// 파일을 넘나들며 8개씩 묶여야 함 (마지막 배치만 작을 수 있음)
+	full := 0
+	for _, count := range service.requests {
+		if count > 8 {
+			t.Errorf("배치 크기 제한을 넘었습니다: %d", count)
+		}
+		if count == 8 {
+			full++
+		}
+	}
+	if full < len(service.requests)-1 || len(service.requests) != (len(service.texts)+7)/8 {
+		t.Errorf("배치가 가득 차지 않았습니다: %v", service.requests)
+	}
+
+	// 임베딩이 요청한 파일과 청크로 돌아와야 함
+	for i, result := range results {
+		if len(result.Embeddings) != 1 || !strings.Contains(service.texts[int(result.Embeddings[0][0])], fmt.Sprintf("class Class%d ", i)) {
+			t.Errorf("%s의 파일 임베딩이 올바르지 않습니다", result.Filename)
+		}
+		for _, chunk := range result.Chunks {
+			method := regexp.MustCompile(`(Run|Stop)\d+`).FindString(chunk.Text)
+			if text := service.texts[int(chunk.Embeddings[0])]; method == "" || !strings.Contains(text, method+"(") {
+				t.Errorf("%s 청크의 임베딩이 다른 텍스트의 것입니다: %q", result.Filename, text)
+			}
+		}
+	}
+}
+
+// rejectingEmbedding은 빈 텍스트나 거부할 단어가 포함된 텍스트가 하나라도 있으면 요청 전체를 거부하는 테스트용 임베딩 서비스입니다.
+type rejectingEmbedding struct {
+	fakeEmbedding
+	reject string
+}
+
+func (f *rejectingEmbedding) CreateEmbeddings(texts []string) ([][]float32, error) {
+	for _, text := range texts {
+		if strings.TrimSpace(text) == "" || strings.Contains(text, f.reject) {
+			f.mu.Lock()
+			f.requests = append(f.requests, -len(texts))
+			f.mu.Unlock()
+			return nil, fmt.Errorf("invalid input: %q", text)
+		}
+	}
+	return f.fakeEmbedding.CreateEmbeddings(texts)
+}
+
+func TestPipelineEmbeddingRejectedInput(t *testing.T) {
+	dir := t.TempDir()
+	sources := map[string]string{
+		"A.cs":     "class A { void Run() { } }\n",
+		"B.cs":     "class B { void Rejected() { } }\n",
+		"C.cs":     "class C { void Stop() { } }\n",
+		"Empty.cs": "   \n",
+	}
+	for name, source := range sources {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte(source), 0644)
+	}
+
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+	service := &rejectingEmbedding{reject: "Rejected"}
+	config := &embeddings.Config{MaxTextSize: 1024, BatchSize: 100, BatchTokens: 100000}
+	pipeline := analyzer.NewPipeline(analyzer.NewAnalyzer(factory, service, config), analyzer.PipelineConfig{ParseWorkers: 1, EmbedWorkers: 1})
+	results, errs := pipeline.Run([]string{dir}, func(folder string, visit func(path string)) error {
+		for _, name := range []string{"A.cs", "B.cs", "C.cs", "Empty.cs"} {
+			visit(filepath.Join(folder, name))
+		}
+		return nil
+	})
+
+	// 거부된 텍스트가 있는 파일만 실패하고, 빈 파일은 임베딩 없이 분석되어야 함
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "B.cs") {
+		t.Errorf("거부된 텍스트가 있는 파일만 실패해야 합니다: %v", errs)
+	}
+	if len(results) != 3 {
+		t.Fatalf("나머지 파일은 분석되어야 합니다: %d", len(results))
+	}
+	for _, result := range results {
+		if result.Filename == "Empty.cs" {
+			if len(result.Embeddings) != 0 {
+				t.Errorf("빈 파일은 임베딩을 요청하지 않아야 합니다")
+			}
+			continue
+		}
+		if len(result.Embeddings) == 0 || len(result.Chunks[0].Embeddings) == 0 {
+			t.Errorf("%s의 임베딩이 없습니다", result.Filename)
+		}
+	}
+	for _, text := range service.texts {
+		if strings.TrimSpace(text) == "" {
+			t.Errorf("빈 텍스트를 요청하면 안 됩니다")
+		}
+	}
+}
+
+func TestChunkEmbeddingReuse(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Service.cs")
+	write := func(path, stop string) {
+		source := "class Service {\n  void Run() { Start(); }\n  void Stop() { " + stop + " }\n}\n"
+		if err := ioutil.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatalf("파일 쓰기 오류: %v", err)
+		}
+	}
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+	config := &embeddings.Config{MaxTextSize: 1024, BatchSize: 8, BatchTokens: 100000}
+
+	// 첫 실행: 파일 전체와 두 청크 모두 임베딩
+	write(path, "Halt();")
+	service := &fakeEmbedding{}
+	first, err := analyzer.NewAnalyzer(factory, service, config).AnalyzeFile(path)
+	if err != nil {
+		t.Fatalf("분석 오류: %v", err)
+	}
+	if len(service.texts) != 3 {
+		t.Fatalf("첫 실행은 파일 전체와 청크 2개를 임베딩해야 합니다: %d", len(service.texts))
+	}
+	if err := analyzer.NewAnalyzer(factory, nil, nil).SaveResult(first); err != nil {
+		t.Fatalf("저장 오류: %v", err)
+	}
+
+	// Stop만 바뀐 새 실행: Run 청크는 이전 SkelChunker 파일에서 재사용하고, 같은 내용의 다른 파일은 요청하지 않음
+	write(path, "Halt(true);")
+	write(filepath.Join(dir, "Copy.cs"), "Halt(true);")
+	service = &fakeEmbedding{}
+	fileAnalyzer := analyzer.NewAnalyzer(factory, service, config)
+	second, err := fileAnalyzer.AnalyzeFile(path)
+	if err != nil {
+		t.Fatalf("분석 오류: %v", err)
+	}
+	copied, err := fileAnalyzer.AnalyzeFile(filepath.Join(dir, "Copy.cs"))
+	if err != nil {
+		t.Fatalf("분석 오류: %v", err)
+	}
+
+	if stats := fileAnalyzer.EmbeddingStats(); stats.Embedded != 1 || stats.Reused != 3 {
+		t.Errorf("바뀐 청크 하나만 임베딩해야 합니다: %+v", stats)
+	}
+	for _, text := range service.texts {
+		if strings.Contains(text, "Run()") && !strings.Contains(text, "class Service") {
+			t.Errorf("바뀌지 않은 청크가 다시 임베딩되었습니다: %q", text)
+		}
+	}
+	for i, chunk := range second.Chunks {
+		if len(chunk.Embeddings) == 0 || len(copied.Chunks[i].Embeddings) == 0 {
+			t.Fatalf("모든 청크에 임베딩이 있어야 합니다")
+		}
+		if strings.Contains(chunk.Text, "Run()") && chunk.Embeddings[0] != first.Chunks[i].Embeddings[0] {
+			t.Errorf("Run 청크는 이전 임베딩을 재사용해야 합니다")
+		}
+	}
+}
+
+func TestEmbeddingModelChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Service.cs")
+	ioutil.WriteFile(path, []byte("class Service {\n  void Run() { }\n  void Stop() { }\n}\n"), 0644)
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+
+	// 모델과 차원별로 분석하고 결과를 저장한 뒤 임베딩 요청 수를 반환
+	analyze := func(modelName string, vectorDim int) (*model.AnalysisResult, int) {
+		service := &fakeEmbedding{}
+		config := &embeddings.Config{ModelName: modelName, VectorDim: vectorDim, MaxTextSize: 1024, BatchSize: 8, BatchTokens: 100000}
+		fileAnalyzer := analyzer.NewAnalyzer(factory, service, config)
+		result, err := fileAnalyzer.AnalyzeFile(path)
+		if err != nil {
+			t.Fatalf("분석 오류: %v", err)
+		}
+		if err := fileAnalyzer.SaveResult(result); err != nil {
+			t.Fatalf("저장 오류: %v", err)
+		}
+		return result, len(service.texts)
+	}
+
+	if result, requested := analyze("model-a", 1); requested != 3 || result.EmbeddingModel != "model-a" {
+		t.Fatalf("첫 실행은 모든 텍스트를 임베딩해야 합니다: %d, %s", requested, result.EmbeddingModel)
+	}
+	if _, requested := analyze("model-a", 1); requested != 0 {
+		t.Errorf("같은 모델이면 저장된 임베딩을 재사용해야 합니다: %d", requested)
+	}
+
+	// 모델이 바뀌면 파일과 청크 모두 다시 임베딩
+	result, requested := analyze("model-b", 1)
+	if requested != 3 || result.EmbeddingModel != "model-b" {
+		t.Errorf("모델이 바뀌면 다시 임베딩해야 합니다: %d, %s", requested, result.EmbeddingModel)
+	}
+
+	// 차원이 바뀌어 벡터 길이가 맞지 않아도 다시 임베딩
+	if _, requested := analyze("model-b", 2); requested != 3 {
+		t.Errorf("차원이 바뀌면 다시 임베딩해야 합니다: %d", requested)
+	}
+}
+
+func TestResultVersion(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Service.cs")
+	ioutil.WriteFile(path, []byte("class Service {\n  void Run() { }\n}\n"), 0644)
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+	factory.RegisterParser(parser.NewJavaScriptParser())
+	fileAnalyzer := analyzer.NewAnalyzer(factory, nil, nil)
+
+	// 분석하고 결과를 저장한 뒤 저장된 결과를 재사용했는지 반환
+	analyze := func() *analyzer.ParsedFile {
+		parsed, err := fileAnalyzer.ParseFile(path)
+		if err != nil {
+			t.Fatalf("분석 오류: %v", err)
+		}
+		if err := fileAnalyzer.SaveResult(parsed.Result); err != nil {
+			t.Fatalf("저장 오류: %v", err)
+		}
+		return parsed
+	}
+
+	if parsed := analyze(); parsed.Cached || parsed.Result.Version != analyzer.ResultVersion || parsed.Result.Parser != "csharp_parser" {
+		t.Fatalf("결과에 버전과 파서가 기록되어야 합니다: %+v", parsed.Result)
+	}
+	if parsed := analyze(); !parsed.Cached {
+		t.Errorf("바뀌지 않은 파일은 저장된 결과를 재사용해야 합니다")
+	}
+
+	// 버전을 기록하지 않은 이전 버전의 결과는 파일이 바뀌지 않아도 다시 만듦
+	resultPath := path + ".SkelChunker"
+	data, err := os.ReadFile(resultPath)
+	if err != nil {
+		t.Fatalf("결과 파일 읽기 오류: %v", err)
+	}
+	old := strings.Replace(string(data), fmt.Sprintf("  \"version\": %d,\n", analyzer.ResultVersion), "", 1)
+	ioutil.WriteFile(resultPath, []byte(old), 0644)
+	if parsed := analyze(); parsed.Cached || parsed.Result.Version != analyzer.ResultVersion {
+		t.Errorf("이전 버전의 결과를 재사용했습니다: %+v", parsed.Result)
+	}
+
+	// 파서 매핑이 바뀌어도 다시 분석
+	if err := factory.Configure(map[string]string{".cs": "javascript_parser"}); err != nil {
+		t.Fatalf("설정 오류: %v", err)
+	}
+	if parsed := analyze(); parsed.Cached || parsed.Result.Parser != "javascript_parser" {
+		t.Errorf("다른 파서로 만든 결과를 재사용했습니다: %+v", parsed.Result)
+	}
+}
+
+func TestEmbeddingCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := embeddings.NewCache(dir, "model-a", 3, 1)
+	if err != nil {
+		t.Fatalf("캐시 생성 오류: %v", err)
+	}
+	service := &fakeEmbedding{}
+	cached := embeddings.NewCachedEmbedding(service, cache)
+	first, err := cached.CreateEmbeddings([]string{"alpha", "beta"})
+	if err != nil || len(first) != 2 {
+		t.Fatalf("임베딩 오류: %v", err)
+	}
+
+	// 다른 실행(새 Cache)에서도 저장된 임베딩을 찾고, 없는 텍스트만 서비스에 요청
+	cache, _ = embeddings.NewCache(dir, "model-a", 3, 1)
+	cached = embeddings.NewCachedEmbedding(service, cache)
+	second, err := cached.CreateEmbeddings([]string{"beta", "gamma", "alpha"})
+	if err != nil {
+		t.Fatalf("임베딩 오류: %v", err)
+	}
+	if second[0][0] != first[1][0] || second[2][0] != first[0][0] || cache.Hits() != 2 || cache.Misses() != 1 {
+		t.Errorf("캐시된 임베딩을 재사용해야 합니다: %v, hits %d, misses %d", second, cache.Hits(), cache.Misses())
+	}
+	if len(service.requests) != 2 || service.requests[1] != 1 {
+		t.Errorf("캐시에 없는 텍스트만 요청해야 합니다: %v", service.requests)
+	}
+
+	// 모델이나 전처리 버전이 다르면 다른 항목
+	other, _ := embeddings.NewCache(dir, "model-a", 3, 2)
+	if _, ok := other.Get("alpha"); ok {
+		t.Errorf("전처리 버전이 다른 캐시 항목을 사용했습니다")
+	}
+
+	// 크기 제한을 넘으면 가장 오래 쓰지 않은 항목부터 삭제
+	usage, err := embeddings.CacheStats(dir)
+	if err != nil || usage.Entries != 3 {
+		t.Fatalf("캐시 항목 수가 올바르지 않습니다: %+v, %v", usage, err)
+	}
+	old := time.Now().Add(-time.Hour)
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			os.Chtimes(path, old, old)
+		}
+		return nil
+	})
+	cache.Get("gamma")
+	pruned, err := embeddings.PruneCache(dir, usage.Bytes/3, 0)
+	if err != nil || pruned.Entries != 2 {
+		t.Fatalf("오래 쓰지 않은 항목 2개를 삭제해야 합니다: %+v, %v", pruned, err)
+	}
+	if _, ok := cache.Get("gamma"); !ok {
+		t.Errorf("최근에 쓴 항목은 남아 있어야 합니다")
+	}
+}
+
+func TestResultOutputTree(t *testing.T) {
+	base := t.TempDir()
+	sources := filepath.Join(base, "src", "shop")
+	if err := os.MkdirAll(sources, 0755); err != nil {
+		t.Fatalf("폴더 생성 오류: %v", err)
+	}
+	ioutil.WriteFile(filepath.Join(sources, "Foo.cs"), []byte("class Foo { void Run() { } }\n"), 0644)
+	ioutil.WriteFile(filepath.Join(sources, "Foo.js"), []byte("function run() { }\n"), 0644)
+
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+	factory.RegisterParser(parser.NewJavaScriptParser())
+	fileAnalyzer := analyzer.NewAnalyzer(factory, nil, nil)
+	outputRoot := filepath.Join(base, "out")
+	if err := fileAnalyzer.ConfigureOutput(analyzer.OutputConfig{Root: outputRoot, BaseDir: base}); err != nil {
+		t.Fatalf("출력 설정 오류: %v", err)
+	}
+
+	for _, name := range []string{"Foo.cs", "Foo.js"} {
+		result, err := fileAnalyzer.AnalyzeFile(filepath.Join(sources, name))
+		if err != nil {
+			t.Fatalf("분석 오류: %v", err)
+		}
+		if result.Path != "src/shop" || result.Filename != name {
+			t.Errorf("결과 경로는 기준 폴더에 대한 상대 경로여야 합니다: %s/%s", result.Path, result.Filename)
+		}
+		if err := fileAnalyzer.SaveResult(result); err != nil {
+			t.Fatalf("저장 오류: %v", err)
+		}
+	}
+
+	// 확장자를 유지하므로 같은 이름의 두 파일이 서로 덮어쓰지 않고, 소스 폴더에는 아무것도 쓰지 않음
+	for _, name := range []string{"Foo.cs.SkelChunker", "Foo.js.SkelChunker"} {
+		if _, err := os.Stat(filepath.Join(outputRoot, "src", "shop", name)); err != nil {
+			t.Errorf("결과 폴더에 %s가 있어야 합니다: %v", name, err)
+		}
+	}
+	if entries, _ := os.ReadDir(sources); len(entries) != 2 {
+		t.Errorf("소스 폴더에 결과 파일이 생겼습니다: %d개", len(entries))
+	}
+	if got := fileAnalyzer.ResultPath("src/shop/Foo.cs"); got != filepath.Join(outputRoot, "src", "shop", "Foo.cs.SkelChunker") {
+		t.Errorf("결과 파일 경로가 올바르지 않습니다: %s", got)
+	}
+}
+
+func TestStaleOutputs(t *testing.T) {
+	base := t.TempDir()
+	sources := filepath.Join(base, "src")
+	os.MkdirAll(sources, 0755)
+	for _, name := range []string{"Deleted", "Moved", "Kept"} {
+		source := fmt.Sprintf("class %s { void Run() { } }\n", name)
+		ioutil.WriteFile(filepath.Join(sources, name+".cs"), []byte(source), 0644)
+	}
+	legacy := filepath.Join(sources, "Old.SkelChunker")
+	ioutil.WriteFile(legacy, []byte("{}"), 0644)
+
+	factory := parser.NewParserFactory()
+	factory.RegisterParser(parser.NewCSharpParser())
+	config := &embeddings.Config{MaxTextSize: 1024, BatchSize: 8, BatchTokens: 100000}
+	outputRoot := filepath.Join(base, "out")
+	newAnalyzer := func(service embeddings.EmbeddingService) *analyzer.Analyzer {
+		fileAnalyzer := analyzer.NewAnalyzer(factory, service, config)
+		if err := fileAnalyzer.ConfigureOutput(analyzer.OutputConfig{Root: outputRoot, BaseDir: base}); err != nil {
+			t.Fatalf("출력 설정 오류: %v", err)
+		}
+		if err := fileAnalyzer.TrackRenames(); err != nil {
+			t.Fatalf("이름 변경 추적 오류: %v", err)
+		}
+		return fileAnalyzer
+	}
+
+	first := newAnalyzer(&fakeEmbedding{})
+	for _, name := range []string{"Deleted", "Moved", "Kept"} {
+		result, err := first.AnalyzeFile(filepath.Join(sources, name+".cs"))
+		if err != nil {
+			t.Fatalf("분석 오류: %v", err)
+		}
+		first.SaveResult(result)
+	}
+
+	// Deleted는 삭제, Moved는 다른 폴더로 이동, Kept는 남아 있지만 분석 대상에서 제외
+	os.Remove(filepath.Join(sources, "Deleted.cs"))
+	os.MkdirAll(filepath.Join(sources, "lib"), 0755)
+	moved := filepath.Join(sources, "lib", "Renamed.cs")
+	os.Rename(filepath.Join(sources, "Moved.cs"), moved)
+
+	service := &fakeEmbedding{}
+	second := newAnalyzer(service)
+	result, err := second.AnalyzeFile(moved)
+	if err != nil {
+		t.Fatalf("분석 오류: %v", err)
+	}
+	if len(service.texts) != 0 || len(result.Embeddings) == 0 || result.Path != "src/lib" {
+		t.Errorf("옮겨진 파일은 이전 결과와 임베딩을 이어받아야 합니다: 요청 %d, %s", len(service.texts), result.Path)
+	}
+	second.SaveResult(result)
+
+	outputs, err := analyzer.ListOutputs(outputRoot)
+	if err != nil {
+		t.Fatalf("결과 목록 오류: %v", err)
+	}
+	stale := second.StaleOutputs(append(outputs, legacy), []string{moved})
+	reasons := make(map[string]string)
+	for _, item := range stale {
+		reasons[filepath.Base(item.Path)] = item.Reason
+		if item.Reason == analyzer.StaleRenamed && item.RenamedTo != "src/lib/Renamed.cs" {
+			t.Errorf("이름 변경 대상이 올바르지 않습니다: %s", item.RenamedTo)
+		}
+	}
+	want := map[string]string{
+		"Deleted.cs.SkelChunker": analyzer.StaleDeleted,
+		"Moved.cs.SkelChunker":   analyzer.StaleRenamed,
+		"Kept.cs.SkelChunker":    analyzer.StaleIgnored,
+		"Old.SkelChunker":        analyzer.StaleLegacy,
+	}
+	if len(reasons) != len(want) {
+		t.Errorf("오래된 결과 목록이 올바르지 않습니다: %v", reasons)
+	}
+	for name, reason := range want {
+		if reasons[name] != reason {
+			t.Errorf("%s의 정리 사유는 %s여야 합니다: %s", name, reason, reasons[name])
+		}
+	}
+
+	if removed, errs := second.RemoveOutputs(stale); len(removed) != 4 || len(errs) != 0 {
+		t.Fatalf("오래된 결과 삭제 오류: %d개, %v", len(removed), errs)
+	}
+	if outputs, _ := analyzer.ListOutputs(outputRoot); len(outputs) != 1 {
+		t.Errorf("옮겨진 파일의 결과만 남아야 합니다: %v", outputs)
+	}
+}
+
+func TestIgnoreMatcher(t *testing.T) {
+	base := t.TempDir()
+	files := map[string]string{
+		".gitignore":                    "bin/\n*.log\n# 주석\n",
+		"src/.skelchunkerignore":        "Migrations/\n*.g.cs\n!Keep.g.cs\n",
+		"src/App.cs":                    "",
+		"src/App.Designer.cs":           "",
+		"src/Model.g.cs":                "",
+		"src/Keep.g.cs":                 "",
+		"src/debug.log":                 "",
+		"src/Migrations/Init.cs":        "",
+		"src/bin/Out.cs":                "",
+		"src/Core/Service.cs":           "",
+		"src/Core/Generated/Client.cs":  "",
+		"src/Core/Generated/.gitignore": "!*.log\n",
+		"src/Core/Generated/trace.log":  "",
+	}
+	for name, content := range files {
+		path := filepath.Join(base, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		ioutil.WriteFile(path, []byte(content), 0644)
+	}
+
+	src := filepath.Join(base, "src")
+	matcher, err := ignore.NewMatcher(src, ignore.Config{
+		Root:      base,
+		FileNames: []string{".gitignore", ".skelchunkerignore"},
+		Exclude:   []string{"**/*.Designer.cs", "/Core/Generated/"},
+	})
+	if err != nil {
+		t.Fatalf("Matcher 생성 오류: %v", err)
+	}
+	want := map[string]bool{
+		"App.cs":                   false,
+		"App.Designer.cs":          true,  // 설정의 제외 패턴
+		"Model.g.cs":               true,  // 하위 폴더의 .skelchunkerignore
+		"Keep.g.cs":                false, // 부정 패턴
+		"debug.log":                true,  // 상위 폴더의 .gitignore
+		"Migrations/Init.cs":       true,  // 폴더 패턴
+		"bin/Out.cs":               true,
+		"Core/Service.cs":          false,
+		"Core/Generated/Client.cs": true, // 분석 폴더에 고정된 폴더 패턴
+		"Core/Generated/trace.log": true, // 제외된 폴더 안의 파일은 다시 포함할 수 없음
+	}
+	for name, ignored := range want {
+		if got := matcher.Ignored(filepath.Join(src, filepath.FromSlash(name)), false); got != ignored {
+			t.Errorf("%s의 제외 여부는 %v여야 합니다: %v", name, ignored, got)
+		}
+	}
+	if !matcher.Ignored(filepath.Join(src, "Migrations"), true) || matcher.Ignored(src, true) {
+		t.Errorf("폴더 제외 여부가 올바르지 않습니다")
+	}
+
+	included, err := ignore.NewMatcher(src, ignore.Config{Include: []string{"Core/", "*.Designer.cs"}})
+	if err != nil {
+		t.Fatalf("Matcher 생성 오류: %v", err)
+	}
+	for name, want := range map[string]bool{"Core/Service.cs": true, "App.Designer.cs": true, "App.cs": false} {
+		if got := included.Included(filepath.Join(src, filepath.FromSlash(name))); got != want {
+			t.Errorf("%s의 포함 여부는 %v여야 합니다: %v", name, want, got)
+		}
+	}
+
+	if _, err := ignore.NewMatcher(src, ignore.Config{Exclude: []string{"[z-a].cs"}}); err == nil {
+		t.Errorf("잘못된 패턴은 오류를 반환해야 합니다")
+	}
+}
@@ -0,0 +1,265 @@
+//go:build treesitter
+
+package parser_test
+
+import (
+	"SkelChunker/src/model"
+	"SkelChunker/src/parser"
+	"SkelChunker/src/project"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTreeSitterParser(t *testing.T) {
+	factory := parser.NewParserFactory()
+	if err := parser.RegisterTreeSitterParsers(factory, nil); err != nil {
+		t.Fatalf("tree-sitter 파서 등록 오류: %v", err)
+	}
+	p, err := factory.GetParserByName("treesitter_python")
+	if err != nil {
+		t.Fatalf("파서를 찾지 못했습니다: %v", err)
+	}
+
+	source := "# 주석\n" +
+		"class Greeter(Base):\n" +
+		"    def hello(self, name):\n" +
+		"        def inner():\n" +
+		"            pass\n" +
+		"        return name\n" +
+		"\n" +
+		"def main():\n" +
+		"    Greeter().hello(\"x\")\n"
+	nodes, chunks, err := p.Parse(source)
+	if err != nil {
+		t.Fatalf("파싱 오류: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Name != "Greeter" || nodes[0].Type != "class" || nodes[0].Kind != "class" || nodes[1].Name != "main" || nodes[1].Type != "function" {
+		t.Fatalf("노드가 올바르지 않습니다: %+v", nodes)
+	}
+	if len(nodes[0].Extends) != 1 || nodes[0].Extends[0] != "Base" {
+		t.Errorf("기본 클래스가 올바르지 않습니다: %v", nodes[0].Extends)
+	}
+	if nodes[0].Signature != "class Greeter(Base)" {
+		t.Errorf("시그니처가 올바르지 않습니다: %s", nodes[0].Signature)
+	}
+
+	members := nodes[0].Members
+	if len(members) != 1 || members[0].Name != "hello" || members[0].Type != "method" || members[0].Signature != "def hello(self, name)" {
+		t.Fatalf("멤버가 올바르지 않습니다: %+v", members)
+	}
+	if members[0].Range.StartLine != 3 || members[0].Range.EndLine != 6 {
+		t.Errorf("멤버 범위가 올바르지 않습니다: %+v", members[0].Range)
+	}
+	calls := nodes[1].Calls
+	if len(calls) != 2 || calls[0].Name != "Greeter" || calls[1].Name != "hello" || calls[1].Receiver != "" {
+		t.Errorf("호출이 올바르지 않습니다: %+v", calls)
+	}
+	if len(chunks) != 2 || source[chunks[1].Range.Start:chunks[1].Range.End] != chunks[1].Text {
+		t.Errorf("청크가 올바르지 않습니다: %+v", chunks)
+	}
+
+	identifiers := p.(parser.IdentifierExtractor).ExtractIdentifiers(source)
+	if len(identifiers) == 0 || identifiers[0].Name != "Greeter" || identifiers[0].Line != 2 || identifiers[0].Column != 7 {
+		t.Errorf("식별자가 올바르지 않습니다: %+v", identifiers)
+	}
+
+	if _, err := parser.NewTreeSitterParser("python", "(unknown_node) @definition.x"); err == nil {
+		t.Error("잘못된 쿼리가 허용되었습니다")
+	}
+
+	java, err := factory.GetParserByName("treesitter_java")
+	if err != nil {
+		t.Fatalf("파서를 찾지 못했습니다: %v", err)
+	}
+	javaNodes, _, err := java.Parse("interface Shape extends Named, Comparable<Shape> { }\n" +
+		"class Circle extends Base implements Shape, Serializable { void draw() { } }\n")
+	if err != nil {
+		t.Fatalf("파싱 오류: %v", err)
+	}
+	if len(javaNodes) != 2 || strings.Join(javaNodes[0].Extends, ",") != "Named,Comparable<Shape>" ||
+		strings.Join(javaNodes[1].Extends, ",") != "Base" || strings.Join(javaNodes[1].Implements, ",") != "Shape,Serializable" {
+		t.Errorf("상속 목록이 올바르지 않습니다: %+v", javaNodes)
+	}
+
+	// 의존 구문은 @import 캡처로 찾음
+	javaImports := importOutline(java.(parser.ImportExtractor).ExtractImports("import java.util.List;\nimport static java.lang.Math.max;\nimport java.io.*;\n"))
+	if got := strings.Join(javaImports, "\n"); got != "import java.util.List names=[] alias= modifiers=[]\n"+
+		"import java.lang.Math.max names=[] alias= modifiers=[static]\n"+
+		"import java.io names=[*] alias= modifiers=[]" {
+		t.Errorf("의존 구문이 올바르지 않습니다:\n%s", got)
+	}
+}
+
+// skeletonOutline은 내장 파서와 tree-sitter 파서가 함께 찾는 정보(Type과 Kind, 상속, 멤버 이름과 시그니처, 호출)를 한 줄씩 나열합니다.
+func skeletonOutline(nodes []model.SkeletonNode, signatures bool) []string {
+	var lines []string
+	calls := func(calls []model.CallSite) string {
+		var names []string
+		for _, call := range calls {
+			names = append(names, call.Receiver+"."+call.Name+"/"+call.Kind)
+		}
+		return strings.Join(names, " ")
+	}
+	for _, node := range nodes {
+		line := fmt.Sprintf("%s/%s %s parent=%s extends=%v implements=%v calls=[%s]", node.Type, node.Kind, node.Name, node.Parent, node.Extends, node.Implements, calls(node.Calls))
+		if signatures {
+			line += " signature=" + node.Signature
+		}
+		lines = append(lines, line)
+		for _, member := range node.Members {
+			line := fmt.Sprintf("  %s %s calls=[%s]", member.Type, member.Name, calls(member.Calls))
+			if signatures {
+				line += " signature=" + member.Signature
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// importOutline은 의존 구문의 종류, 경로, 가져온 이름, 별칭, 제어자를 한 줄씩 나열합니다.
+func importOutline(imports []model.Import) []string {
+	var lines []string
+	for _, imp := range imports {
+		lines = append(lines, fmt.Sprintf("%s %s names=%v alias=%s modifiers=%v", imp.Kind, imp.Path, imp.Names, imp.Alias, imp.Modifiers))
+	}
+	return lines
+}
+
+// compareWithTreeSitter는 같은 소스를 내장 파서와 tree-sitter 파서로 파싱하여 스켈레톤과 의존 구문을 비교합니다.
+func compareWithTreeSitter(t *testing.T, builtin parser.Parser, treeSitterName string, source string, signatures bool) []model.SkeletonNode {
+	t.Helper()
+	factory := parser.NewParserFactory()
+	if err := parser.RegisterTreeSitterParsers(factory, nil); err != nil {
+		t.Fatalf("tree-sitter 파서 등록 오류: %v", err)
+	}
+	treeSitter, err := factory.GetParserByName(treeSitterName)
+	if err != nil {
+		t.Fatalf("파서를 찾지 못했습니다: %v", err)
+	}
+	if len(treeSitter.GetFileExtensions()) != 0 {
+		t.Errorf("%s가 내장 파서의 확장자를 가져갔습니다: %v", treeSitterName, treeSitter.GetFileExtensions())
+	}
+
+	want, _, err := builtin.Parse(source)
+	if err != nil {
+		t.Fatalf("내장 파서 파싱 오류: %v", err)
+	}
+	got, chunks, err := treeSitter.Parse(source)
+	if err != nil {
+		t.Fatalf("tree-sitter 파싱 오류: %v", err)
+	}
+	wantLines, gotLines := skeletonOutline(want, signatures), skeletonOutline(got, signatures)
+	if strings.Join(wantLines, "\n") != strings.Join(gotLines, "\n") {
+		t.Errorf("tree-sitter 결과가 내장 파서와 다릅니다\n내장:\n%s\ntree-sitter:\n%s", strings.Join(wantLines, "\n"), strings.Join(gotLines, "\n"))
+	}
+	for _, chunk := range chunks {
+		if source[chunk.Range.Start:chunk.Range.End] != chunk.Text {
+			t.Errorf("청크 범위가 올바르지 않습니다: %+v", chunk)
+		}
+	}
+
+	wantImports := importOutline(builtin.(parser.ImportExtractor).ExtractImports(source))
+	gotImports := importOutline(treeSitter.(parser.ImportExtractor).ExtractImports(source))
+	if len(wantImports) == 0 || strings.Join(wantImports, "\n") != strings.Join(gotImports, "\n") {
+		t.Errorf("tree-sitter 의존 구문이 내장 파서와 다릅니다\n내장:\n%s\ntree-sitter:\n%s", strings.Join(wantImports, "\n"), strings.Join(gotImports, "\n"))
+	}
+	return got
+}
+
+func TestTreeSitterCSharpMatchesBuiltin(t *testing.T) {
+	source := "using System;\n" +
+		"namespace Shop.Orders\n" +
+		"{\n" +
+		"    public interface IService : IDisposable\n" +
+		"    {\n" +
+		"        void Run();\n" +
+		"    }\n" +
+		"\n" +
+		"    public class OrderService : ServiceBase, IService, IComparable<OrderService>\n" +
+		"    {\n" +
+		"        private int count;\n" +
+		"        public string Name { get; set; }\n" +
+		"\n" +
+		"        public OrderService(int count)\n" +
+		"        {\n" +
+		"            this.count = count;\n" +
+		"        }\n" +
+		"\n" +
+		"        public void Run()\n" +
+		"        {\n" +
+		"            Console.WriteLine(Name);\n" +
+		"            var order = new Order();\n" +
+		"            this.Save<Order>(order);\n" +
+		"            Validate(order);\n" +
+		"        }\n" +
+		"\n" +
+		"        public enum State { Open, Closed }\n" +
+		"    }\n" +
+		"\n" +
+		"    public struct Point : IEquatable<Point>\n" +
+		"    {\n" +
+		"        public int X;\n" +
+		"    }\n" +
+		"}\n"
+	nodes := compareWithTreeSitter(t, parser.NewCSharpParser(), "treesitter_csharp", source, true)
+	if len(nodes) < 2 || nodes[1].Visibility != "public" || len(nodes[1].Members) != 3 || nodes[1].Members[1].Visibility != "public" {
+		t.Errorf("접근 제한자가 올바르지 않습니다: %+v", nodes)
+	}
+}
+
+func TestTreeSitterCSharpPartialTypes(t *testing.T) {
+	factory := parser.NewParserFactory()
+	if err := parser.RegisterTreeSitterParsers(factory, nil); err != nil {
+		t.Fatalf("tree-sitter 파서 등록 오류: %v", err)
+	}
+	csharp, err := factory.GetParserByName("treesitter_csharp")
+	if err != nil {
+		t.Fatalf("파서를 찾지 못했습니다: %v", err)
+	}
+
+	// 내장 파서와 같은 Type과 제어자를 기록하므로 partial 인터페이스와 구조체도 병합됨
+	sources := map[string]string{
+		"A.cs": "namespace Shop { public partial interface IStore { void Load(); } partial struct Key { int a; } }\n",
+		"B.cs": "namespace Shop { partial interface IStore { void Save(); } public partial struct Key { void Reset() { } } }\n",
+	}
+	var results []*model.AnalysisResult
+	for _, file := range []string{"A.cs", "B.cs"} {
+		nodes, _, err := csharp.Parse(sources[file])
+		if err != nil {
+			t.Fatalf("%s 파싱 오류: %v", file, err)
+		}
+		results = append(results, &model.AnalysisResult{Path: "src", Filename: file, Skeleton: nodes})
+	}
+
+	var merged []string
+	for _, partial := range project.MergePartialTypes(results) {
+		merged = append(merged, fmt.Sprintf("%s:%s:%s:%d", partial.Skeleton.Kind, partial.Skeleton.Name, partial.Skeleton.Visibility, len(partial.Skeleton.Members)))
+	}
+	if got := strings.Join(merged, ","); got != "interface:IStore:public:2,struct:Key:public:1" {
+		t.Errorf("partial 타입이 병합되지 않았습니다: %s", got)
+	}
+}
+
+func TestTreeSitterJavaScriptMatchesBuiltin(t *testing.T) {
+	source := "import { Base } from './base.js';\n" +
+		"\n" +
+		"class Animal extends Base {\n" +
+		"  constructor(name) {\n" +
+		"    super(name);\n" +
+		"    this.name = name;\n" +
+		"  }\n" +
+		"\n" +
+		"  speak() {\n" +
+		"    console.log(this.name);\n" +
+		"    helper();\n" +
+		"  }\n" +
+		"}\n" +
+		"\n" +
+		"function main() {\n" +
+		"  const animal = new Animal('cat');\n" +
+		"  animal.speak();\n" +
+		"}\n"
+	compareWithTreeSitter(t, parser.NewJavaScriptParser(), "treesitter_javascript", source, false)
+}